@@ -19,6 +19,7 @@ import (
 
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/extension"
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/healthcheck"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
 )
 
 // Name is a const for the name of this component.
@@ -45,6 +46,9 @@ func NewCommand() *cobra.Command {
 	verflag.AddFlags(flags)
 	opts.addFlags(flags)
 
+	cmd.AddCommand(newRenderCommand())
+	cmd.AddCommand(newValidateCommand())
+
 	return cmd
 }
 
@@ -65,6 +69,11 @@ func run(ctx context.Context, log logr.Logger, o *options) error {
 
 	log.Info("Adding controllers to manager")
 	extension.DefaultAddOptions.GardenClusterIdentity = o.gardenClusterIdentity
+	extension.DefaultAddOptions.Bootstrap = o.bootstrapOptions
+	extension.DefaultAddOptions.FluxManifests = o.installOptions.FluxManifests
+	extension.DefaultAddOptions.ManifestCache = fluxmanifests.NewCache(o.manifestCacheSizeMB<<20, o.manifestCacheDir)
+	extension.DefaultAddOptions.ImageDigests = o.installOptions.ImageDigests
+	extension.DefaultAddOptions.FluxVersions = o.installOptions.FluxVersions
 	o.extensionOptions.Completed().Apply(&extension.DefaultAddOptions.Controller)
 	o.healthOptions.Completed().Apply(&healthcheck.DefaultAddOptions.Controller)
 	o.heartbeatOptions.Completed().Apply(&heartbeat.DefaultAddOptions)
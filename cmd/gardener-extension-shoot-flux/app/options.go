@@ -5,6 +5,7 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -28,6 +29,8 @@ import (
 	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/extension"
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/healthcheck"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
 var _ initrun.Options = &options{}
@@ -45,12 +48,65 @@ type options struct {
 	optionAggregator   extensionscmdcontroller.OptionAggregator
 
 	gardenClusterIdentity string
+	bootstrapOptions      extension.BootstrapOptions
+	installOptions        installOptions
+	manifestCacheSizeMB   int64
+	manifestCacheDir      string
 
 	// completed options
 	RESTConfig     *rest.Config
 	ManagerOptions manager.Options
 }
 
+// installOptions configure how Flux is installed. They are shared by the controller and the offline subcommands.
+type installOptions struct {
+	fluxManifestsDir   string
+	imageDigestCatalog string
+	fluxVersions       []string
+	fluxMinimumVersion string
+
+	// completed options
+	FluxManifests *fluxmanifests.Source
+	ImageDigests  fluxmanifests.DigestCatalog
+	FluxVersions  *fluxversions.Catalog
+}
+
+func (o *installOptions) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.fluxManifestsDir, "flux-manifests-dir", "", "Directory containing the Flux install manifests in one subdirectory per Flux version (e.g., v2.9.2/). If set, the manifests are never downloaded from GitHub")
+	fs.StringVar(&o.imageDigestCatalog, "image-digest-catalog", "", "YAML file mapping Flux component images (<component>:<tag>) to digests for pinning the images. Extends the catalog shipped with the extension")
+	fs.StringSliceVar(&o.fluxVersions, "flux-versions", nil, "Additional Flux versions (e.g., v2.9.3) supported in addition to the versions shipped with the extension")
+	fs.StringVar(&o.fluxMinimumVersion, "flux-minimum-version", "", "Minimum Flux version that can be installed. Lower versions are rejected and never selected by version constraints")
+}
+
+func (o *installOptions) complete() error {
+	o.FluxManifests = fluxmanifests.NewSource(o.fluxManifestsDir)
+
+	imageDigests, err := fluxmanifests.LoadDigestCatalog(o.imageDigestCatalog)
+	if err != nil {
+		return err
+	}
+	o.ImageDigests = imageDigests
+
+	fluxVersions, err := fluxversions.DefaultCatalog(o.fluxVersions, o.fluxMinimumVersion)
+	if err != nil {
+		return err
+	}
+	o.FluxVersions = fluxVersions
+
+	return nil
+}
+
+func (o *installOptions) validate() error {
+	if o.fluxManifestsDir != "" {
+		if info, err := os.Stat(o.fluxManifestsDir); err != nil {
+			return fmt.Errorf("invalid flux manifests directory: %w", err)
+		} else if !info.IsDir() {
+			return fmt.Errorf("invalid flux manifests directory: %q is not a directory", o.fluxManifestsDir)
+		}
+	}
+	return nil
+}
+
 // newOptions creates a new options instance.
 func newOptions() *options {
 	opts := &options{
@@ -81,7 +137,9 @@ func newOptions() *options {
 			extensionscmdcontroller.Switch(extensionshealthcheckcontroller.ControllerName, healthcheck.AddToManager),
 			extensionscmdcontroller.Switch(extensionsheartbeatcontroller.ControllerName, extensionsheartbeatcontroller.AddToManager),
 		),
-		reconcileOptions: &extensionscmdcontroller.ReconcilerOptions{},
+		reconcileOptions:    &extensionscmdcontroller.ReconcilerOptions{},
+		bootstrapOptions:    extension.DefaultBootstrapOptions(),
+		manifestCacheSizeMB: extension.DefaultManifestCacheSize >> 20,
 	}
 
 	opts.optionAggregator = extensionscmdcontroller.NewOptionAggregator(
@@ -101,6 +159,10 @@ func newOptions() *options {
 func (o *options) addFlags(fs *pflag.FlagSet) {
 	o.optionAggregator.AddFlags(fs)
 	fs.StringVar(&o.gardenClusterIdentity, "garden-cluster-identity", "garden", "Identity of the Garden cluster. Should be set by the controllerinstallation controller")
+	o.bootstrapOptions.AddFlags(fs)
+	o.installOptions.addFlags(fs)
+	fs.Int64Var(&o.manifestCacheSizeMB, "install-manifest-cache-size-mb", o.manifestCacheSizeMB, "Maximum size in MiB of the generated Flux install manifests cached in memory")
+	fs.StringVar(&o.manifestCacheDir, "install-manifest-cache-dir", "", "Directory to persist the generated Flux install manifests in. If empty, the manifests are only cached in memory")
 }
 
 func (o *options) Complete() error {
@@ -108,6 +170,10 @@ func (o *options) Complete() error {
 		return err
 	}
 
+	if err := o.installOptions.complete(); err != nil {
+		return err
+	}
+
 	// customize rest config
 	o.RESTConfig = o.restOptions.Completed().Config
 
@@ -118,7 +184,8 @@ func (o *options) Complete() error {
 
 	o.ManagerOptions.Client.Cache = &client.CacheOptions{
 		DisableFor: []client.Object{
-			&corev1.Secret{}, // applied for ManagedResources
+			&corev1.Secret{},    // applied for ManagedResources
+			&corev1.ConfigMap{}, // only read for additionalConfigMapResources
 		},
 	}
 
@@ -142,6 +209,24 @@ func (o *options) Validate() error {
 		return err
 	}
 
+	if err := o.bootstrapOptions.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.installOptions.validate(); err != nil {
+		return err
+	}
+
+	if o.manifestCacheSizeMB < 0 {
+		return fmt.Errorf("install manifest cache size must not be negative")
+	}
+
+	if o.manifestCacheDir != "" {
+		if err := os.MkdirAll(o.manifestCacheDir, 0o750); err != nil {
+			return fmt.Errorf("invalid install manifest cache directory: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/offline"
+)
+
+// inputOptions configure how the offline subcommands read their inputs.
+type inputOptions struct {
+	shootMetadata offline.ShootMetadata
+}
+
+func (o *inputOptions) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.shootMetadata.Name, "shoot-name", "shoot", "Name of the Shoot for FluxConfig inputs")
+	fs.StringVar(&o.shootMetadata.Namespace, "shoot-namespace", "garden-local", "Project namespace of the Shoot for FluxConfig inputs")
+}
+
+// readInputs reads the inputs from the given file, "-" reads from stdin.
+func (o *inputOptions) readInputs(stdin io.Reader, file string) ([]offline.Input, error) {
+	if file == "-" {
+		return offline.ReadInputs("stdin", stdin, o.shootMetadata)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return offline.ReadInputs(file, f, o.shootMetadata)
+}
+
+func newRenderCommand() *cobra.Command {
+	var (
+		inputOpts             inputOptions
+		installOpts           installOptions
+		gardenClusterIdentity string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render FILE",
+		Short: "Print all objects the extension would apply to the shoot",
+		Long: `Print all objects the extension would apply to the shoot when bootstrapping Flux for the given Shoot or
+FluxConfig manifest ("-" reads from stdin) as multi-document YAML: the Flux install manifest, namespaces, source,
+Kustomization, shoot-info ConfigMap, and Secrets. The providerConfig is decoded, defaulted, and validated like in the
+extension. No cluster is accessed, so the data of referenced resources and all Secret data are redacted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := installOpts.validate(); err != nil {
+				return err
+			}
+			if err := installOpts.complete(); err != nil {
+				return err
+			}
+
+			inputs, err := inputOpts.readInputs(cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			if len(inputs) != 1 {
+				return fmt.Errorf("expected exactly one Shoot with the %s extension or FluxConfig, found %d", fluxv1alpha1.ExtensionType, len(inputs))
+			}
+
+			out, err := offline.Render(cmd.Context(), inputs[0], offline.RenderOptions{
+				GardenClusterIdentity: gardenClusterIdentity,
+				FluxManifests:         installOpts.FluxManifests,
+				ImageDigests:          installOpts.ImageDigests,
+				FluxVersions:          installOpts.FluxVersions,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", inputs[0].Source, err)
+			}
+
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	inputOpts.addFlags(flags)
+	installOpts.addFlags(flags)
+	flags.StringVar(&gardenClusterIdentity, "garden-cluster-identity", "garden", "Identity of the Garden cluster used in the shoot-info ConfigMap")
+
+	return cmd
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/offline"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// validationResult is the result of validating a single input in the JSON output.
+type validationResult struct {
+	Source string            `json:"source"`
+	Valid  bool              `json:"valid"`
+	Errors []validationError `json:"errors,omitempty"`
+
+	allErrs field.ErrorList
+}
+
+type validationError struct {
+	Type     field.ErrorType `json:"type"`
+	Field    string          `json:"field"`
+	BadValue any             `json:"badValue,omitempty"`
+	Detail   string          `json:"detail,omitempty"`
+}
+
+func newValidateCommand() *cobra.Command {
+	var (
+		inputOpts   inputOptions
+		installOpts installOptions
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate FILE...",
+		Short: "Validate the providerConfigs of Shoot or FluxConfig manifests",
+		Long: `Validate the providerConfigs of the given Shoot or FluxConfig manifests ("-" reads from stdin) like the
+extension does: the providerConfig is decoded, defaulted, and validated. For Shoot manifests, the referenced resources
+are checked against Shoot.spec.resources. The command exits with a non-zero code if any providerConfig is invalid.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != outputText && output != outputJSON {
+				return fmt.Errorf("unsupported output format %q, must be one of %q, %q", output, outputText, outputJSON)
+			}
+			if err := installOpts.validate(); err != nil {
+				return err
+			}
+			if err := installOpts.complete(); err != nil {
+				return err
+			}
+			// the results are already printed, only report the summary
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+
+			var results []validationResult
+			for _, file := range args {
+				inputs, err := inputOpts.readInputs(cmd.InOrStdin(), file)
+				if err != nil {
+					return err
+				}
+
+				for _, input := range inputs {
+					results = append(results, newValidationResult(input.Source, offline.Validate(input, installOpts.FluxVersions)))
+				}
+			}
+
+			if err := printValidationResults(cmd.OutOrStdout(), output, results); err != nil {
+				return err
+			}
+
+			invalid := 0
+			for _, result := range results {
+				if !result.Valid {
+					invalid++
+				}
+			}
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d providerConfigs are invalid", invalid, len(results))
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	inputOpts.addFlags(flags)
+	installOpts.addFlags(flags)
+	flags.StringVarP(&output, "output", "o", outputText, fmt.Sprintf("Output format, one of %q, %q", outputText, outputJSON))
+
+	return cmd
+}
+
+func newValidationResult(source string, allErrs field.ErrorList) validationResult {
+	result := validationResult{Source: source, Valid: len(allErrs) == 0, allErrs: allErrs}
+	for _, err := range allErrs {
+		result.Errors = append(result.Errors, validationError{
+			Type:     err.Type,
+			Field:    err.Field,
+			BadValue: err.BadValue,
+			Detail:   err.Detail,
+		})
+	}
+	return result
+}
+
+func printValidationResults(w io.Writer, output string, results []validationResult) error {
+	if output == outputJSON {
+		if results == nil {
+			results = []validationResult{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	for _, result := range results {
+		if result.Valid {
+			if _, err := fmt.Fprintf(w, "%s: valid\n", result.Source); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: invalid\n", result.Source); err != nil {
+			return err
+		}
+		for _, fieldErr := range result.allErrs {
+			if _, err := fmt.Fprintf(w, "  - %s\n", fieldErr.Error()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
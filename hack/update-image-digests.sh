@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Resolves the digests of the Flux component images of the given Flux versions and writes them to the digest catalog.
+# Usage: hack/update-image-digests.sh <catalog> <version>...
+
+catalog="$1"
+shift
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+# keep the existing entries
+grep -E '^[a-z0-9-]+:[^ ]+: sha256:[a-f0-9]{64}$' "$catalog" > "$tmp/entries" || true
+
+for version in "$@"; do
+  echo "> Resolving image digests of Flux $version"
+  mkdir -p "$tmp/$version"
+  curl -sSfL "https://github.com/fluxcd/flux2/releases/download/$version/manifests.tar.gz" | tar xz -C "$tmp/$version"
+
+  for image in $(grep -rhoE 'image: ghcr.io/fluxcd/[a-z0-9-]+:[^ ]+' "$tmp/$version" | sed 's/^image: ghcr.io\/fluxcd\///' | sort -u); do
+    component="${image%%:*}"
+    tag="${image#*:}"
+    token="$(curl -sSf "https://ghcr.io/token?scope=repository:fluxcd/$component:pull" | sed -E 's/.*"token":"([^"]+)".*/\1/')"
+    digest="$(curl -sSfI \
+      -H "Authorization: Bearer $token" \
+      -H "Accept: application/vnd.oci.image.index.v1+json" \
+      -H "Accept: application/vnd.docker.distribution.manifest.list.v2+json" \
+      "https://ghcr.io/v2/fluxcd/$component/manifests/$tag" | tr -d '\r' | awk 'tolower($1) == "docker-content-digest:" { print $2 }')"
+    echo "$component:$tag: $digest"
+    echo "$component:$tag: $digest" >> "$tmp/entries"
+  done
+done
+
+{
+  sed '/^[^#]/,$d' "$catalog"
+  if [ -s "$tmp/entries" ]; then
+    tac "$tmp/entries" | sort -u -t" " -k1,1
+  else
+    echo "{}"
+  fi
+} > "$tmp/catalog"
+mv "$tmp/catalog" "$catalog"
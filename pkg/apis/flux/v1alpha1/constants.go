@@ -8,4 +8,51 @@ const (
 	// successfully bootstrapping Flux once. It is used for skipping reconciliation of the Flux resources after a first
 	// initial bootstrapping.
 	ConditionBootstrapped = "FluxBootstrapped"
+	// ConditionInSync is a condition in the Extension status that reports whether the bootstrapped source and
+	// Kustomization in the shoot still match the templates in the providerConfig. It is maintained by the health check
+	// controller and lists the differing fields if the objects have drifted.
+	ConditionInSync = "FluxConfigInSync"
 )
+
+const (
+	// LabelManagedBy is set by the extension on all resources it manages in the shoot. It must not be declared in the
+	// labels of additional resources.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+)
+
+const (
+	// AnnotationOperation is an annotation on the Extension or Shoot that requests an operation of the extension.
+	AnnotationOperation = "flux.extensions.gardener.cloud/operation"
+	// OperationRebootstrap requests to bootstrap Flux again as if it was never bootstrapped: the Flux install
+	// manifest, source, and Kustomization are applied again. The annotation is removed from the Extension once the
+	// bootstrap has been restarted. On the Shoot, the annotation must be removed by the user before requesting another
+	// rebootstrap.
+	OperationRebootstrap = "rebootstrap"
+)
+
+const (
+	// ShootInfoConfigMapName is the name of the shoot-info ConfigMap managed by the extension in the shoot. It must not
+	// be used as the target name of additional ConfigMaps.
+	ShootInfoConfigMapName = "shoot-info"
+	// ShootInfoSecretName is the name of the shoot-info Secret managed by the extension in the shoot. It must not be
+	// used as the target name of additional Secrets.
+	ShootInfoSecretName = "shoot-info"
+)
+
+const (
+	// ShootInfoSecretKeyAPIServerURL is the key of the shoot-info Secret containing the external URL of the Shoot's
+	// API server.
+	ShootInfoSecretKeyAPIServerURL = "SHOOT_INFO_API_SERVER_URL"
+	// ShootInfoSecretKeyCABundle is the key of the shoot-info Secret containing the CA bundle of the Shoot's API server.
+	ShootInfoSecretKeyCABundle = "SHOOT_INFO_CA_BUNDLE"
+	// ShootInfoSecretKeyIngressDomain is the key of the shoot-info Secret containing the wildcard domain for ingresses
+	// in the Shoot.
+	ShootInfoSecretKeyIngressDomain = "SHOOT_INFO_INGRESS_DOMAIN"
+)
+
+// ShootInfoSecretKeys are all keys supported in the shoot-info Secret.
+var ShootInfoSecretKeys = []string{
+	ShootInfoSecretKeyAPIServerURL,
+	ShootInfoSecretKeyCABundle,
+	ShootInfoSecretKeyIngressDomain,
+}
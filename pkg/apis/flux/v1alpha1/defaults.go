@@ -20,6 +20,9 @@ const (
 	// defaultFluxVersion is maintained by renovate via a customManager. Don't
 	// change this without also updating the renovate config.
 	defaultFluxVersion = "v2.9.2"
+
+	// DefaultFluxVersion is the Flux version that is installed if FluxInstallation.version is not set.
+	DefaultFluxVersion = defaultFluxVersion
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -91,6 +94,10 @@ func SetDefaults_FluxInstallation(obj *FluxInstallation) {
 }
 
 func SetDefaults_Source(obj *Source) {
+	if obj.WaitForReady == nil {
+		obj.WaitForReady = ptr.To(true)
+	}
+
 	if obj.Template == nil {
 		return
 	}
@@ -139,6 +146,10 @@ func SetDefaults_Source(obj *Source) {
 }
 
 func SetDefaults_Kustomization(obj *Kustomization) {
+	if obj.WaitForReady == nil {
+		obj.WaitForReady = ptr.To(true)
+	}
+
 	SetDefaults_Flux_Kustomization(&obj.Template)
 }
 
@@ -9,6 +9,7 @@ import (
 	. "github.com/gardener/gardener/pkg/utils/test/matchers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/utils/ptr"
@@ -77,6 +78,13 @@ var _ = Describe("FluxConfig defaulting", func() {
 			Expect(gitRepo.Name).To(Equal("flux-system"))
 			Expect(gitRepo.Namespace).To(Equal("flux-system"))
 			Expect(gitRepo.Spec.Interval.Duration).To(Equal(time.Minute))
+			Expect(obj.Source.WaitForReady).To(PointTo(BeTrue()))
+		})
+
+		It("should not overwrite waitForReady", func() {
+			obj.Source.WaitForReady = ptr.To(false)
+			SetObjectDefaults_FluxConfig(obj)
+			Expect(obj.Source.WaitForReady).To(PointTo(BeFalse()))
 		})
 
 		It("should default secretRef.name to flux-system if secretResourceName is set", func() {
@@ -229,6 +237,12 @@ var _ = Describe("FluxConfig defaulting", func() {
 			Expect(obj.Kustomization.Template.Name).To(Equal("flux-system"))
 			Expect(obj.Kustomization.Template.Namespace).To(Equal("flux-system"))
 			Expect(obj.Kustomization.Template.Spec.Interval.Duration).To(Equal(time.Minute))
+			Expect(obj.Kustomization.WaitForReady).To(PointTo(BeTrue()))
+		})
+		It("should not overwrite waitForReady", func() {
+			obj.Kustomization.WaitForReady = ptr.To(false)
+			SetObjectDefaults_FluxConfig(obj)
+			Expect(obj.Kustomization.WaitForReady).To(PointTo(BeFalse()))
 		})
 		It("should handle if the source is omitted", func() {
 			obj.Source = nil
@@ -24,28 +24,143 @@ type FluxConfig struct {
 	// If provided, "Source" must also be provided.
 	// +optional
 	Kustomization *Kustomization `json:"kustomization,omitempty"`
+	// Bootstrap configures the polling and timeouts of the initial bootstrap of Flux.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
 
 	// AdditionalSecretResources to sync to the shoot.
 	// Secrets referenced here are only created if they don't exist in the shoot yet.
 	// When a secret is removed from this list, it is deleted in the shoot.
 	// +optional
 	AdditionalSecretResources []AdditionalResource `json:"additionalSecretResources,omitempty"`
+
+	// AdditionalConfigMapResources to sync to the shoot.
+	// ConfigMaps referenced here are created or updated in the shoot.
+	// When a ConfigMap is removed from this list, it is deleted in the shoot.
+	// +optional
+	AdditionalConfigMapResources []AdditionalResource `json:"additionalConfigMapResources,omitempty"`
+
+	// ShootInfo configures additional content of the shoot-info ConfigMap.
+	// +optional
+	ShootInfo *ShootInfo `json:"shootInfo,omitempty"`
+
+	// DryRun makes the extension compute the changes to the Flux installation, source, Kustomization, Secrets, and
+	// ConfigMaps in the shoot without applying them. The changes are determined by dry-run requests to the shoot's API
+	// server and reported in the Extension's providerStatus and events. While set, Flux is neither bootstrapped nor are
+	// the synced objects updated.
+	// +optional
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// Suspend suspends the reconciliation of the bootstrapped source and Kustomization in the shoot by setting their
+	// spec.suspend field, also after Flux has been bootstrapped. Setting it to false resumes the reconciliation and
+	// requests an immediate reconciliation of both objects. Objects suspended in their templates stay suspended.
+	// While set, the bootstrap doesn't wait for the source and Kustomization to get ready.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+}
+
+// Bootstrap configures the polling and timeouts of the initial bootstrap of Flux.
+// Unset fields are defaulted by the extension's operator. All values are capped at the maximum timeout and minimum
+// poll interval configured by the operator.
+type Bootstrap struct {
+	// PollInterval is the interval in which the readiness of the bootstrapped objects is checked.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// InstallTimeout is the maximum time to wait for the Flux installation to get ready.
+	// +optional
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+	// SourceTimeout is the maximum time to wait for the source object to get ready.
+	// +optional
+	SourceTimeout *metav1.Duration `json:"sourceTimeout,omitempty"`
+	// KustomizationTimeout is the maximum time to wait for the Kustomization to get ready.
+	// Defaults to the Kustomization's spec.timeout if set.
+	// +optional
+	KustomizationTimeout *metav1.Duration `json:"kustomizationTimeout,omitempty"`
+}
+
+// ShootInfo configures additional content of the shoot-info ConfigMap.
+// Keys generated by the extension take precedence over keys configured here.
+type ShootInfo struct {
+	// Data contains additional key/value pairs that are added to the shoot-info ConfigMap.
+	// Keys must be valid Flux variable names.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+	// LabelPrefixes selects Shoot labels by prefix that are added to the shoot-info ConfigMap.
+	// The label keys are normalised to "SHOOT_INFO_LABEL_<KEY>", where all characters that are not allowed in
+	// variable names are replaced by underscores, e.g., "example.com/cost-center" becomes
+	// "SHOOT_INFO_LABEL_EXAMPLE_COM_COST_CENTER".
+	// +optional
+	LabelPrefixes []string `json:"labelPrefixes,omitempty"`
+	// AnnotationPrefixes selects Shoot annotations by prefix that are added to the shoot-info ConfigMap.
+	// The annotation keys are normalised to "SHOOT_INFO_ANNOTATION_<KEY>" like label keys.
+	// +optional
+	AnnotationPrefixes []string `json:"annotationPrefixes,omitempty"`
+	// Extensions selects fields of the providerConfigs of other extensions enabled on the Shoot that are added to the
+	// shoot-info ConfigMap. The enabled extensions themselves are always published.
+	// +optional
+	Extensions []ShootInfoExtension `json:"extensions,omitempty"`
+	// Namespaces are additional namespaces in which the shoot-info ConfigMap is created, e.g., for tenant
+	// Kustomizations that cannot reference the ConfigMap in the Flux namespace. The namespaces are created if they
+	// don't exist. When a namespace is removed from this list, the ConfigMap is deleted in that namespace.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Secret configures a shoot-info Secret for sensitive information about the Shoot, which should not be published
+	// in the shoot-info ConfigMap. The Secret is only created if it is configured.
+	// +optional
+	Secret *ShootInfoSecret `json:"secret,omitempty"`
+}
+
+// ShootInfoSecret configures the content of the shoot-info Secret.
+type ShootInfoSecret struct {
+	// Keys selects the keys that are added to the shoot-info Secret.
+	// Supported keys are: SHOOT_INFO_API_SERVER_URL, SHOOT_INFO_CA_BUNDLE, SHOOT_INFO_INGRESS_DOMAIN.
+	Keys []string `json:"keys"`
+}
+
+// ShootInfoExtension selects fields of the providerConfig of an extension enabled on the Shoot.
+type ShootInfoExtension struct {
+	// Type is the type of the extension, e.g., "shoot-dns-service".
+	Type string `json:"type"`
+	// Fields are paths to fields in the providerConfig separated by dots, e.g., "spec.mode".
+	// The values are added as "SHOOT_INFO_EXTENSION_<TYPE>_<FIELD>" with normalised keys.
+	// Only select fields that don't contain sensitive data, the shoot-info ConfigMap is readable by everyone with
+	// access to the Flux namespace.
+	Fields []string `json:"fields"`
 }
 
 // AdditionalResource to sync to the shoot.
 type AdditionalResource struct {
 	// Name references a resource under Shoot.spec.resources.
 	Name string `json:"name"`
-	// TargetName optionally overwrites the name of the secret in the shoot.
+	// TargetName optionally overwrites the name of the resource in the shoot.
 	// +optional
 	TargetName *string `json:"targetName,omitempty"`
+	// TargetNamespace optionally overwrites the namespace of the resource in the shoot.
+	// Defaults to the Flux namespace. The namespace is created if it does not exist.
+	// +optional
+	TargetNamespace *string `json:"targetNamespace,omitempty"`
+	// Keys optionally maps data keys of the referenced resource to data keys in the shoot.
+	// If set, only the listed keys are copied. An empty value keeps the original key.
+	// +optional
+	Keys map[string]string `json:"keys,omitempty"`
+	// CopyLabels specifies whether the labels of the referenced resource are copied to the resource in the shoot.
+	// +optional
+	CopyLabels *bool `json:"copyLabels,omitempty"`
+	// Labels are added to the resource in the shoot. They take precedence over copied labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the resource in the shoot. Annotations added by others are kept.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // FluxInstallation configures the Flux installation in the Shoot cluster.
 type FluxInstallation struct {
 	// renovate updates the doc string. See renovate config for more details
 
-	// Version specifies the Flux version that should be installed.
+	// Version specifies the Flux version that should be installed. It is either an exact version, e.g., "v2.6.4", or a
+	// semver constraint, e.g., "~2.9", which resolves to the newest supported version matching the constraint when
+	// Flux is installed. The version must be in the catalog of versions supported by the extension.
 	// Defaults to "v2.9.2".
 	// +optional
 	Version *string `json:"version,omitempty"`
@@ -68,6 +183,31 @@ type FluxInstallation struct {
 	// See https://fluxcd.io/flux/installation/configuration/optional-components/
 	// +optional
 	ComponentsExtra []string `json:"componentsExtra,omitempty"`
+
+	// Images overrides the images of individual Flux components, e.g., if the controllers are mirrored under different
+	// repository names. Overrides take precedence over Registry.
+	// +optional
+	Images []ComponentImage `json:"images,omitempty"`
+	// PinImageDigests pins the images of all Flux components to the digests from the digest catalog shipped with the
+	// extension. Images with an explicit digest in Images are pinned to that digest instead. The providerConfig is
+	// rejected before installing Flux if the catalog doesn't contain the digest of an image.
+	// +optional
+	PinImageDigests *bool `json:"pinImageDigests,omitempty"`
+}
+
+// ComponentImage overrides the image of a Flux component.
+type ComponentImage struct {
+	// Component is the name of the Flux component, e.g., "source-controller".
+	Component string `json:"component"`
+	// Repository replaces the image repository including the registry, e.g., "registry.example.com/mirror/source-controller".
+	// +optional
+	Repository *string `json:"repository,omitempty"`
+	// Tag replaces the image tag.
+	// +optional
+	Tag *string `json:"tag,omitempty"`
+	// Digest pins the image to the given digest, e.g., "sha256:0123...".
+	// +optional
+	Digest *string `json:"digest,omitempty"`
 }
 
 // Source configures how to bootstrap a Flux source object.
@@ -114,6 +254,11 @@ type Source struct {
 	// (spec.secretRef.name) if specified in Template.
 	// +optional
 	SecretResourceName *string `json:"secretResourceName,omitempty"`
+	// WaitForReady specifies whether the extension waits for the source to get ready during the initial bootstrap.
+	// If false, the source is created without waiting and its readiness is only reported in the Extension status.
+	// Defaults to true.
+	// +optional
+	WaitForReady *bool `json:"waitForReady,omitempty"`
 }
 
 // Kustomization configures how to bootstrap a Flux Kustomization object.
@@ -125,4 +270,130 @@ type Kustomization struct {
 	// - metadata.namespace is defaulted to "flux-system"
 	// - spec.interval is defaulted to "1m"
 	Template kustomizev1.Kustomization `json:"template"`
+	// WaitForReady specifies whether the extension waits for the Kustomization to get ready during the initial
+	// bootstrap. If false, the Kustomization is created without waiting and its readiness is only reported in the
+	// Extension status.
+	// Defaults to true.
+	// +optional
+	WaitForReady *bool `json:"waitForReady,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FluxStatus is the providerStatus of the shoot-flux Extension.
+type FluxStatus struct {
+	metav1.TypeMeta `json:",inline"`
+	// Bootstrap contains the progress of the initial bootstrap of Flux.
+	// +optional
+	Bootstrap *BootstrapStatus `json:"bootstrap,omitempty"`
+	// Version is the Flux version installed by the last bootstrap.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Source reports the readiness of the source object if the extension doesn't wait for it to get ready.
+	// +optional
+	Source *ObjectStatus `json:"source,omitempty"`
+	// Kustomization reports the readiness of the Kustomization if the extension doesn't wait for it to get ready.
+	// +optional
+	Kustomization *ObjectStatus `json:"kustomization,omitempty"`
+	// DryRun reports the changes the extension would make to the shoot if dryRun is set in the providerConfig.
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// ShootRebootstrapStarted is true if the rebootstrap requested by the operation annotation on the Shoot has been
+	// started. It is reset once the annotation is removed from the Shoot, so that the rebootstrap is only performed
+	// once per annotation.
+	// +optional
+	ShootRebootstrapStarted bool `json:"shootRebootstrapStarted,omitempty"`
+	// Suspended is true if the reconciliation of the source and Kustomization in the shoot has been suspended by the
+	// suspend field of the providerConfig.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+	// Hibernation tracks the hibernation of the shoot, so that the extension can request an immediate reconciliation of
+	// the bootstrapped objects when the shoot wakes up. It is removed once the wake-up has been handled.
+	// +optional
+	Hibernation *HibernationStatus `json:"hibernation,omitempty"`
+}
+
+// HibernationStatus tracks the hibernation of the shoot.
+type HibernationStatus struct {
+	// Hibernated is true while the shoot is hibernated. It is false after the shoot has woken up until the Flux
+	// controllers are ready.
+	Hibernated bool `json:"hibernated"`
+	// LastTransitionTime is the time the extension noticed that the shoot has been hibernated or woken up.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// DryRunStatus reports the result of the last dry run.
+type DryRunStatus struct {
+	// LastRunTime is the time of the last dry run.
+	LastRunTime metav1.Time `json:"lastRunTime"`
+	// Changes are the changes the extension would make to objects in the shoot.
+	// +optional
+	Changes []ObjectChange `json:"changes,omitempty"`
+}
+
+// ChangeAction is the action the extension would perform on an object in the shoot.
+type ChangeAction string
+
+const (
+	// ChangeActionCreate means that the object would be created.
+	ChangeActionCreate ChangeAction = "Create"
+	// ChangeActionUpdate means that the object would be updated.
+	ChangeActionUpdate ChangeAction = "Update"
+	// ChangeActionDelete means that the object would be deleted.
+	ChangeActionDelete ChangeAction = "Delete"
+)
+
+// ObjectChange is a change the extension would make to an object in the shoot.
+type ObjectChange struct {
+	// Action is the action the extension would perform.
+	Action ChangeAction `json:"action"`
+	// APIVersion is the API version of the object.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// Fields are the paths of the fields that would change on update, e.g., "spec.ref.branch". Values are omitted, as
+	// they might be sensitive. At most 10 fields are listed.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ObjectStatus reports the readiness of a Flux object in the shoot.
+type ObjectStatus struct {
+	// Ready is true if the object's Ready condition is true.
+	Ready bool `json:"ready"`
+	// Message describes why the object is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BootstrapPhase is a phase of the initial bootstrap of Flux.
+type BootstrapPhase string
+
+const (
+	// BootstrapPhaseInstall means that the Flux install manifest has been applied and the extension waits for the
+	// installation to get ready.
+	BootstrapPhaseInstall BootstrapPhase = "Install"
+	// BootstrapPhaseSource means that the extension waits for the Flux source object to get ready.
+	BootstrapPhaseSource BootstrapPhase = "Source"
+	// BootstrapPhaseKustomization means that the extension waits for the Flux Kustomization to get ready.
+	BootstrapPhaseKustomization BootstrapPhase = "Kustomization"
+	// BootstrapPhaseDone means that Flux has been bootstrapped successfully.
+	BootstrapPhaseDone BootstrapPhase = "Done"
+)
+
+// BootstrapStatus contains the progress of the initial bootstrap of Flux.
+type BootstrapStatus struct {
+	// Phase is the current phase of the bootstrap.
+	Phase BootstrapPhase `json:"phase"`
+	// LastTransitionTime is the time the bootstrap entered the current phase.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// WaitingEventRecorded is set once an Event was recorded for waiting on the current phase, so that the Event is not
+	// recorded again for every poll.
+	// +optional
+	WaitingEventRecorded bool `json:"waitingEventRecorded,omitempty"`
 }
@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"maps"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -11,19 +13,24 @@ import (
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
 	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
-// ValidateFluxConfig validates a FluxConfig object.
-func ValidateFluxConfig(fluxConfig *fluxv1alpha1.FluxConfig, shoot *gardencorev1beta1.Shoot, fldPath *field.Path) field.ErrorList {
+// ValidateFluxConfig validates a FluxConfig object. If versions is nil, the Flux version is not validated against the
+// catalog of supported versions.
+func ValidateFluxConfig(fluxConfig *fluxv1alpha1.FluxConfig, shoot *gardencorev1beta1.Shoot, versions *fluxversions.Catalog, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	if fluxConfig.Flux != nil {
-		allErrs = append(allErrs, ValidateFluxInstallation(fluxConfig.Flux, fldPath.Child("flux"))...)
+		allErrs = append(allErrs, ValidateFluxInstallation(fluxConfig.Flux, versions, fldPath.Child("flux"))...)
 	}
 
 	if (fluxConfig.Source == nil) && (fluxConfig.Kustomization != nil) {
@@ -41,16 +48,56 @@ func ValidateFluxConfig(fluxConfig *fluxv1alpha1.FluxConfig, shoot *gardencorev1
 		allErrs = append(allErrs, ValidateKustomization(fluxConfig.Kustomization, fldPath.Child("kustomization"))...)
 	}
 	allErrs = append(allErrs, ValidateAdditionalSecretResources(fluxConfig.AdditionalSecretResources, shoot, fldPath.Child("additionalSecretResources"))...)
+	allErrs = append(allErrs, ValidateAdditionalConfigMapResources(fluxConfig.AdditionalConfigMapResources, shoot, fldPath.Child("additionalConfigMapResources"))...)
+	// resources without a target namespace are synced to the Flux namespace, which might not be defaulted yet
+	var fluxNamespace string
+	if fluxConfig.Flux != nil {
+		fluxNamespace = ptr.Deref(fluxConfig.Flux.Namespace, "")
+	}
+	allErrs = append(allErrs, validateAdditionalResourceTargets(fluxConfig.AdditionalSecretResources, shoot, fluxNamespace, fluxv1alpha1.ShootInfoSecretName, fldPath.Child("additionalSecretResources"))...)
+	allErrs = append(allErrs, validateAdditionalResourceTargets(fluxConfig.AdditionalConfigMapResources, shoot, fluxNamespace, fluxv1alpha1.ShootInfoConfigMapName, fldPath.Child("additionalConfigMapResources"))...)
+
+	if fluxConfig.ShootInfo != nil {
+		allErrs = append(allErrs, ValidateShootInfo(fluxConfig.ShootInfo, fldPath.Child("shootInfo"))...)
+	}
+
+	if fluxConfig.Bootstrap != nil {
+		allErrs = append(allErrs, ValidateBootstrap(fluxConfig.Bootstrap, fldPath.Child("bootstrap"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateBootstrap validates a Bootstrap object.
+func ValidateBootstrap(bootstrap *fluxv1alpha1.Bootstrap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validatePositive := func(duration *metav1.Duration, fldPath *field.Path) {
+		if duration != nil && duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, duration.Duration.String(), "must be positive"))
+		}
+	}
+	validatePositive(bootstrap.PollInterval, fldPath.Child("pollInterval"))
+	validatePositive(bootstrap.InstallTimeout, fldPath.Child("installTimeout"))
+	validatePositive(bootstrap.SourceTimeout, fldPath.Child("sourceTimeout"))
+	validatePositive(bootstrap.KustomizationTimeout, fldPath.Child("kustomizationTimeout"))
 
 	return allErrs
 }
 
 var requiredComponents = []string{"kustomize-controller", "source-controller"}
 
-// ValidateFluxInstallation validates a FluxInstallation object.
-func ValidateFluxInstallation(fluxInstallation *fluxv1alpha1.FluxInstallation, fldPath *field.Path) field.ErrorList {
+// ValidateFluxInstallation validates a FluxInstallation object. If versions is nil, the Flux version is not validated
+// against the catalog of supported versions.
+func ValidateFluxInstallation(fluxInstallation *fluxv1alpha1.FluxInstallation, versions *fluxversions.Catalog, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if version := fluxInstallation.Version; version != nil && versions != nil {
+		if _, err := versions.Resolve(*version); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), *version, err.Error()))
+		}
+	}
+
 	if namespace := fluxInstallation.Namespace; namespace != nil && *namespace != "" {
 		for _, msg := range apivalidation.ValidateNamespaceName(*namespace, false) {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), *namespace, msg))
@@ -66,6 +113,46 @@ func ValidateFluxInstallation(fluxInstallation *fluxv1alpha1.FluxInstallation, f
 		}
 	}
 
+	allErrs = append(allErrs, ValidateComponentImages(fluxInstallation.Images, fldPath.Child("images"))...)
+
+	return allErrs
+}
+
+var (
+	imageRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(:[0-9]+)?(/[a-z0-9]+([._-]+[a-z0-9]+)*)*$`)
+	imageTagRegex        = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	imageDigestRegex     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// ValidateComponentImages validates a list of ComponentImage objects.
+func ValidateComponentImages(images []fluxv1alpha1.ComponentImage, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	components := sets.New[string]()
+	for i, image := range images {
+		idxPath := fldPath.Index(i)
+
+		if image.Component == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("component"), "component is required"))
+		} else if components.Has(image.Component) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("component"), image.Component))
+		}
+		components.Insert(image.Component)
+
+		if image.Repository == nil && image.Tag == nil && image.Digest == nil {
+			allErrs = append(allErrs, field.Required(idxPath, "at least one of repository, tag, or digest is required"))
+		}
+		if image.Repository != nil && !imageRepositoryRegex.MatchString(*image.Repository) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("repository"), *image.Repository, "must be a valid image repository without tag or digest"))
+		}
+		if image.Tag != nil && !imageTagRegex.MatchString(*image.Tag) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("tag"), *image.Tag, "must be a valid image tag"))
+		}
+		if image.Digest != nil && !imageDigestRegex.MatchString(*image.Digest) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("digest"), *image.Digest, "must be a sha256 digest of the form sha256:<64 hex characters>"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -218,24 +305,199 @@ func ValidateAdditionalSecretResources(additionalResources []fluxv1alpha1.Additi
 	}
 
 	for i, r := range additionalResources {
-		if ptr.Deref(r.TargetName, "") != "" && len(validation.IsDNS1123Subdomain(*r.TargetName)) > 0 {
-			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("targetName"), *r.TargetName, "must be a valid resource name"))
-		}
+		allErrs = append(allErrs, validateAdditionalResource(r, fldPath.Index(i))...)
 		allErrs = append(allErrs, validateSecretResource(shoot.Spec.Resources, fldPath.Index(i).Child("name"), r.Name)...)
 	}
 
 	return allErrs
 }
 
+// ValidateAdditionalConfigMapResources validates additionalConfigMapResources
+func ValidateAdditionalConfigMapResources(additionalResources []fluxv1alpha1.AdditionalResource, shoot *gardencorev1beta1.Shoot, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(additionalResources) == 0 {
+		return allErrs
+	}
+
+	for i, r := range additionalResources {
+		allErrs = append(allErrs, validateAdditionalResource(r, fldPath.Index(i))...)
+		allErrs = append(allErrs, validateConfigMapResource(shoot.Spec.Resources, fldPath.Index(i).Child("name"), r.Name)...)
+	}
+
+	return allErrs
+}
+
+// validateAdditionalResource validates the fields of an additional resource that don't depend on its kind.
+func validateAdditionalResource(r fluxv1alpha1.AdditionalResource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ptr.Deref(r.TargetName, "") != "" && len(validation.IsDNS1123Subdomain(*r.TargetName)) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("targetName"), *r.TargetName, "must be a valid resource name"))
+	}
+	if namespace := ptr.Deref(r.TargetNamespace, ""); namespace != "" {
+		for _, msg := range apivalidation.ValidateNamespaceName(namespace, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("targetNamespace"), namespace, msg))
+		}
+	}
+
+	targetKeys := sets.New[string]()
+	for _, key := range slices.Sorted(maps.Keys(r.Keys)) {
+		keyPath := fldPath.Child("keys").Key(key)
+		for _, msg := range validation.IsConfigMapKey(key) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, msg))
+		}
+
+		targetKey := r.Keys[key]
+		if targetKey == "" {
+			targetKey = key
+		} else {
+			for _, msg := range validation.IsConfigMapKey(targetKey) {
+				allErrs = append(allErrs, field.Invalid(keyPath, targetKey, msg))
+			}
+		}
+		if targetKeys.Has(targetKey) {
+			allErrs = append(allErrs, field.Duplicate(keyPath, targetKey))
+		}
+		targetKeys.Insert(targetKey)
+	}
+
+	labelsPath := fldPath.Child("labels")
+	allErrs = append(allErrs, metav1validation.ValidateLabels(r.Labels, labelsPath)...)
+	if _, ok := r.Labels[fluxv1alpha1.LabelManagedBy]; ok {
+		allErrs = append(allErrs, field.Forbidden(labelsPath.Key(fluxv1alpha1.LabelManagedBy), "label is managed by the extension"))
+	}
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(r.Annotations, fldPath.Child("annotations"))...)
+
+	return allErrs
+}
+
+// validateAdditionalResourceTargets validates that the given additional resources of the same kind are synced to
+// distinct objects in the shoot, and not to the shoot-info object of that kind, which is managed by the extension.
+func validateAdditionalResourceTargets(additionalResources []fluxv1alpha1.AdditionalResource, shoot *gardencorev1beta1.Shoot, defaultNamespace, reservedName string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	targets := sets.New[string]()
+	for i, r := range additionalResources {
+		idxPath := fldPath.Index(i)
+
+		name, namePath := ptr.Deref(r.TargetName, ""), idxPath.Child("targetName")
+		if name == "" {
+			resource := v1beta1helper.GetResourceByName(shoot.Spec.Resources, r.Name)
+			if resource == nil {
+				// reported by validateResource
+				continue
+			}
+			name, namePath = resource.ResourceRef.Name, idxPath.Child("name")
+		}
+		if name == "" {
+			// the shoot validation rejects resource references without a name
+			continue
+		}
+		if name == reservedName {
+			allErrs = append(allErrs, field.Invalid(namePath, name, "target name "+reservedName+" is reserved for the shoot-info object managed by the extension"))
+		}
+
+		namespace := ptr.Deref(r.TargetNamespace, "")
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		target := namespace + "/" + name
+		if targets.Has(target) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, target))
+		}
+		targets.Insert(target)
+	}
+
+	return allErrs
+}
+
 func validateSecretResource(resources []gardencorev1beta1.NamedResourceReference, fldPath *field.Path, name string) field.ErrorList {
+	return validateResource(resources, fldPath, name, "Secret", "secret")
+}
+
+func validateConfigMapResource(resources []gardencorev1beta1.NamedResourceReference, fldPath *field.Path, name string) field.ErrorList {
+	return validateResource(resources, fldPath, name, "ConfigMap", "configmap")
+}
+
+func validateResource(resources []gardencorev1beta1.NamedResourceReference, fldPath *field.Path, name, kind, description string) field.ErrorList {
 	allErrs := field.ErrorList{}
 	r := v1beta1helper.GetResourceByName(resources, name)
 	if r == nil {
-		allErrs = append(allErrs, field.Invalid(fldPath, name, "secret resource name does not match any of the resource names in Shoot.spec.resources[].name"))
+		allErrs = append(allErrs, field.Invalid(fldPath, name, description+" resource name does not match any of the resource names in Shoot.spec.resources[].name"))
 		return allErrs
 	}
-	if r.ResourceRef.Kind != "Secret" {
-		allErrs = append(allErrs, field.Invalid(fldPath, r.Name, "secret resource name references a Shoot.spec.resources[], which is not a secret"))
+	if r.ResourceRef.Kind != kind {
+		allErrs = append(allErrs, field.Invalid(fldPath, r.Name, description+" resource name references a Shoot.spec.resources[], which is not a "+description))
+	}
+	return allErrs
+}
+
+// fluxVariableNameRegex matches valid variable names for Flux's post-build substitution.
+var fluxVariableNameRegex = regexp.MustCompile(`^[_[:alpha:]][_[:alpha:][:digit:]]*$`)
+
+// ValidateShootInfo validates a ShootInfo object.
+func ValidateShootInfo(shootInfo *fluxv1alpha1.ShootInfo, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, key := range slices.Sorted(maps.Keys(shootInfo.Data)) {
+		if !fluxVariableNameRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("data").Key(key), key, "must be a valid variable name matching "+fluxVariableNameRegex.String()))
+		}
+	}
+	for i, prefix := range shootInfo.LabelPrefixes {
+		if prefix == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("labelPrefixes").Index(i), "prefix must not be empty"))
+		}
+	}
+	for i, prefix := range shootInfo.AnnotationPrefixes {
+		if prefix == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("annotationPrefixes").Index(i), "prefix must not be empty"))
+		}
+	}
+
+	extensionTypes := sets.New[string]()
+	for i, extension := range shootInfo.Extensions {
+		extensionPath := fldPath.Child("extensions").Index(i)
+		if extension.Type == "" {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("type"), "type must not be empty"))
+		} else if extensionTypes.Has(extension.Type) {
+			allErrs = append(allErrs, field.Duplicate(extensionPath.Child("type"), extension.Type))
+		}
+		extensionTypes.Insert(extension.Type)
+
+		if len(extension.Fields) == 0 {
+			allErrs = append(allErrs, field.Required(extensionPath.Child("fields"), "must select at least one field"))
+		}
+		for j, fieldPath := range extension.Fields {
+			if slices.Contains(strings.Split(fieldPath, "."), "") {
+				allErrs = append(allErrs, field.Invalid(extensionPath.Child("fields").Index(j), fieldPath, "must be a dot-separated path without empty segments"))
+			}
+		}
+	}
+
+	namespaces := sets.New[string]()
+	for i, namespace := range shootInfo.Namespaces {
+		namespacePath := fldPath.Child("namespaces").Index(i)
+		for _, msg := range apivalidation.ValidateNamespaceName(namespace, false) {
+			allErrs = append(allErrs, field.Invalid(namespacePath, namespace, msg))
+		}
+		if namespaces.Has(namespace) {
+			allErrs = append(allErrs, field.Duplicate(namespacePath, namespace))
+		}
+		namespaces.Insert(namespace)
 	}
+
+	if shootInfo.Secret != nil {
+		keysPath := fldPath.Child("secret", "keys")
+		if len(shootInfo.Secret.Keys) == 0 {
+			allErrs = append(allErrs, field.Required(keysPath, "must select at least one key"))
+		}
+		for i, key := range shootInfo.Secret.Keys {
+			if !slices.Contains(fluxv1alpha1.ShootInfoSecretKeys, key) {
+				allErrs = append(allErrs, field.NotSupported(keysPath.Index(i), key, fluxv1alpha1.ShootInfoSecretKeys))
+			}
+		}
+	}
+
 	return allErrs
 }
@@ -1,6 +1,9 @@
 package validation_test
 
 import (
+	"strings"
+	"time"
+
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	"github.com/fluxcd/pkg/apis/meta"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
@@ -9,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -16,6 +20,7 @@ import (
 
 	. "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 	. "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1/validation"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
 var _ = Describe("FluxConfig validation", func() {
@@ -23,11 +28,16 @@ var _ = Describe("FluxConfig validation", func() {
 		rootFldPath *field.Path
 		fluxConfig  *FluxConfig
 		shoot       *gardencorev1beta1.Shoot
+		versions    *fluxversions.Catalog
 	)
 
 	BeforeEach(func() {
 		rootFldPath = field.NewPath("root")
 
+		var err error
+		versions, err = fluxversions.NewCatalog([]string{"v2.8.0", "v2.9.1", "v2.9.2"}, "v2.9.0")
+		Expect(err).NotTo(HaveOccurred())
+
 		gitRepoTemplate := encodeSourceTemplate(&sourcev1.GitRepository{
 			Spec: sourcev1.GitRepositorySpec{
 				Reference: &sourcev1.GitRepositoryRef{
@@ -54,13 +64,13 @@ var _ = Describe("FluxConfig validation", func() {
 	})
 
 	It("should allow basic valid object", func() {
-		Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+		Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 	})
 
 	It("should allow having neither source nor kustomization", func() {
 		fluxConfig.Source = nil
 		fluxConfig.Kustomization = nil
-		Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+		Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 	})
 
 	Describe("FluxInstallation validation", func() {
@@ -71,14 +81,14 @@ var _ = Describe("FluxConfig validation", func() {
 		It("should allow valid namespace names", func() {
 			fluxConfig.Flux.Namespace = ptr.To("my-flux-system")
 
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 		})
 
 		It("should deny invalid namespace names", func() {
 			fluxConfig.Flux.Namespace = ptr.To("this is definitely not a valid namespace")
 
 			Expect(
-				ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 			).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 				"Type":  Equal(field.ErrorTypeInvalid),
 				"Field": Equal("root.flux.namespace"),
@@ -88,12 +98,12 @@ var _ = Describe("FluxConfig validation", func() {
 		It("should check if the required components are present", func() {
 			fluxConfig.Flux.Components = []string{"kustomize-controller", "foo-controller"}
 			fluxConfig.Flux.ComponentsExtra = []string{"source-controller"}
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 		})
 
 		It("should error if a required component is present", func() {
 			fluxConfig.Flux.Components = []string{"kustomize-controller"}
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(ConsistOf(
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":   Equal(field.ErrorTypeInvalid),
 					"Field":  Equal("root.flux.components"),
@@ -101,12 +111,81 @@ var _ = Describe("FluxConfig validation", func() {
 				})),
 			))
 		})
+
+		It("should allow supported versions and constraints", func() {
+			for _, version := range []string{"v2.9.2", "~2.9", ">= 2.9.0, < 3.0.0"} {
+				fluxConfig.Flux.Version = ptr.To(version)
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty(), version)
+			}
+		})
+
+		It("should deny versions outside the catalog or below the minimum", func() {
+			for _, version := range []string{"v2.9.3", "~2.8", "v2.8.0", "not a version"} {
+				fluxConfig.Flux.Version = ptr.To(version)
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  Equal(field.ErrorTypeInvalid),
+						"Field": Equal("root.flux.version"),
+					})),
+				), version)
+			}
+		})
+
+		It("should not validate the version without a catalog", func() {
+			fluxConfig.Flux.Version = ptr.To("v0.0.1")
+			Expect(ValidateFluxConfig(fluxConfig, shoot, nil, rootFldPath)).To(BeEmpty())
+		})
+
+		It("should allow valid image overrides", func() {
+			fluxConfig.Flux.Images = []ComponentImage{
+				{Component: "source-controller", Repository: ptr.To("registry.example.com:5000/mirror/flux-source-controller"), Tag: ptr.To("v1.9.3")},
+				{Component: "kustomize-controller", Digest: ptr.To("sha256:" + strings.Repeat("a", 64))},
+			}
+			fluxConfig.Flux.PinImageDigests = ptr.To(true)
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+
+		It("should deny invalid image overrides", func() {
+			fluxConfig.Flux.Images = []ComponentImage{
+				{Component: "source-controller", Repository: ptr.To("registry.example.com/source-controller:v1.9.3")},
+				{Component: "source-controller", Tag: ptr.To("-invalid")},
+				{Component: "kustomize-controller", Digest: ptr.To("sha256:abc")},
+				{Component: "helm-controller"},
+				{Tag: ptr.To("v1.0.0")},
+			}
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.flux.images[0].repository"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("root.flux.images[1].component"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.flux.images[1].tag"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.flux.images[2].digest"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.flux.images[3]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.flux.images[4].component"),
+				})),
+			))
+		})
 	})
 
 	Describe("Source validation", func() {
 		It("should deny only omitting the source", func() {
 			fluxConfig.Source = nil
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(ConsistOf(
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.source"),
@@ -124,7 +203,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow omitting apiVersion and kind", func() {
@@ -139,7 +218,7 @@ var _ = Describe("FluxConfig validation", func() {
 				// Explicitly clear GVK to test omitting it
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should validate based on decoded template type", func() {
@@ -154,7 +233,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(BeEmpty())
 			})
 		})
@@ -170,7 +249,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.ref"),
@@ -187,7 +266,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.ref"),
@@ -205,7 +284,7 @@ var _ = Describe("FluxConfig validation", func() {
 					mutate(gitRepo.Spec.Reference)
 					fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
-					ExpectWithOffset(1, ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+					ExpectWithOffset(1, ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 				}
 
 				test(func(ref *sourcev1.GitRepositoryRef) { ref.Branch = "develop" })
@@ -229,7 +308,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.url"),
@@ -249,7 +328,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(gitRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow specifying both secretRef and secretResourceName", func() {
@@ -273,7 +352,7 @@ var _ = Describe("FluxConfig validation", func() {
 					},
 				}}
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should deny specifying a secretResourceName without a matching resource", func() {
@@ -295,7 +374,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}}
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.source.secretResourceName"),
@@ -322,7 +401,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}}
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.secretRef"),
@@ -345,7 +424,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.SecretResourceName = nil
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.secretResourceName"),
@@ -366,7 +445,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(BeEmpty())
 			})
 
@@ -374,7 +453,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = nil
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template"),
@@ -408,7 +487,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow omitting apiVersion and kind", func() {
@@ -423,7 +502,7 @@ var _ = Describe("FluxConfig validation", func() {
 				// Explicitly clear GVK
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should validate OCI template based on decoded type", func() {
@@ -438,7 +517,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(BeEmpty())
 			})
 		})
@@ -468,7 +547,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.ref"),
@@ -485,7 +564,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.source.template.spec.ref"),
@@ -503,7 +582,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow setting semver reference", func() {
@@ -517,7 +596,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow setting digest reference", func() {
@@ -531,7 +610,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 		})
 
@@ -562,7 +641,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.url"),
@@ -581,7 +660,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.source.template.spec.url"),
@@ -599,7 +678,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 		})
 
@@ -631,7 +710,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.Template = encodeSourceTemplate(ociRepo)
 				fluxConfig.Source.SecretResourceName = nil
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow specifying both secretRef and secretResourceName", func() {
@@ -655,7 +734,7 @@ var _ = Describe("FluxConfig validation", func() {
 					},
 				}}
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should deny specifying a secretResourceName without a matching resource", func() {
@@ -677,7 +756,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}}
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.source.secretResourceName"),
@@ -704,7 +783,7 @@ var _ = Describe("FluxConfig validation", func() {
 				}}
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.template.spec.secretRef"),
@@ -727,7 +806,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Source.SecretResourceName = nil
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.source.secretResourceName"),
@@ -739,7 +818,7 @@ var _ = Describe("FluxConfig validation", func() {
 	Describe("Kustomization validation", func() {
 		It("should deny only omitting the kustomization", func() {
 			fluxConfig.Kustomization = nil
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(ConsistOf(
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeInvalid),
 					"Field": Equal("root.kustomization"),
@@ -750,14 +829,14 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Kustomization.Template.APIVersion = "kustomize.toolkit.fluxcd.io/v1"
 				fluxConfig.Kustomization.Template.Kind = "Kustomization"
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should allow omitting apiVersion and kind", func() {
 				fluxConfig.Kustomization.Template.APIVersion = ""
 				fluxConfig.Kustomization.Template.Kind = ""
 
-				Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+				Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 			})
 
 			It("should deny using unsupported apiVersion", func() {
@@ -765,7 +844,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Kustomization.Template.Kind = "Kustomization"
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(
 					PointTo(MatchFields(IgnoreExtras, Fields{
 						"Type":  Equal(field.ErrorTypeNotSupported),
@@ -783,7 +862,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Kustomization.Template.Kind = "HelmRelease"
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(
 					PointTo(MatchFields(IgnoreExtras, Fields{
 						"Type":  Equal(field.ErrorTypeNotSupported),
@@ -802,7 +881,7 @@ var _ = Describe("FluxConfig validation", func() {
 				fluxConfig.Kustomization.Template.Spec.Path = ""
 
 				Expect(
-					ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+					ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 				).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":  Equal(field.ErrorTypeRequired),
 					"Field": Equal("root.kustomization.template.spec.path"),
@@ -813,7 +892,7 @@ var _ = Describe("FluxConfig validation", func() {
 	Describe("additionalSecretResources validation", func() {
 		It("should allow specifying nothing", func() {
 			fluxConfig.AdditionalSecretResources = nil
-			Expect(ValidateFluxConfig(fluxConfig, shoot, rootFldPath)).To(BeEmpty())
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
 		})
 		It("should find all errors", func() {
 			fluxConfig.AdditionalSecretResources = []AdditionalResource{
@@ -837,7 +916,7 @@ var _ = Describe("FluxConfig validation", func() {
 				},
 			}
 			Expect(
-				ValidateFluxConfig(fluxConfig, shoot, rootFldPath),
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
 			).To(ConsistOf(
 				PointTo(MatchFields(IgnoreExtras, Fields{
 					"Type":   Equal(field.ErrorTypeInvalid),
@@ -857,6 +936,385 @@ var _ = Describe("FluxConfig validation", func() {
 			))
 		})
 	})
+	Describe("additional resource fields validation", func() {
+		BeforeEach(func() {
+			shoot.Spec.Resources = []gardencorev1beta1.NamedResourceReference{{
+				Name: "valid",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{
+					Kind: "Secret",
+				},
+			}}
+		})
+		It("should allow a valid target namespace and key mapping", func() {
+			fluxConfig.AdditionalSecretResources = []AdditionalResource{{
+				Name:            "valid",
+				TargetNamespace: ptr.To("app"),
+				Keys: map[string]string{
+					"user": "username",
+					"pass": "password",
+					"ca":   "",
+				},
+				CopyLabels: ptr.To(true),
+				Labels: map[string]string{
+					"reconcile.fluxcd.io/watch": "Enabled",
+				},
+				Annotations: map[string]string{
+					"example.com/owner": "team-a",
+				},
+			}}
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+		It("should find all errors", func() {
+			fluxConfig.AdditionalSecretResources = []AdditionalResource{{
+				Name:            "valid",
+				TargetNamespace: ptr.To("Invalid_Namespace"),
+				Keys: map[string]string{
+					"invalid/key": "valid",
+					"user":        "username",
+					"username":    "",
+					"pass":        "invalid key",
+				},
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "someone",
+					"invalid label":                "value",
+				},
+				Annotations: map[string]string{
+					"invalid annotation": "value",
+				},
+			}}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.additionalSecretResources[0].targetNamespace"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalSecretResources[0].keys[invalid/key]"),
+					"BadValue": Equal("invalid/key"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalSecretResources[0].keys[pass]"),
+					"BadValue": Equal("invalid key"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("root.additionalSecretResources[0].keys[username]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeForbidden),
+					"Field": Equal("root.additionalSecretResources[0].labels[app.kubernetes.io/managed-by]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.additionalSecretResources[0].labels"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.additionalSecretResources[0].annotations"),
+				})),
+			))
+		})
+	})
+	Describe("additional resource targets validation", func() {
+		BeforeEach(func() {
+			fluxConfig.Flux = &FluxInstallation{Namespace: ptr.To("flux-system")}
+			shoot.Spec.Resources = []gardencorev1beta1.NamedResourceReference{
+				{
+					Name: "secret",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "Secret",
+						Name: "secret",
+					},
+				},
+				{
+					Name: "shoot-info-secret",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "Secret",
+						Name: "shoot-info",
+					},
+				},
+				{
+					Name: "configmap",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "ConfigMap",
+						Name: "configmap",
+					},
+				},
+				{
+					Name: "shoot-info-configmap",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "ConfigMap",
+						Name: "shoot-info",
+					},
+				},
+			}
+		})
+		It("should allow distinct targets", func() {
+			fluxConfig.AdditionalSecretResources = []AdditionalResource{
+				{Name: "secret"},
+				{Name: "secret", TargetName: ptr.To("other")},
+				{Name: "secret", TargetNamespace: ptr.To("app")},
+				{Name: "shoot-info-secret", TargetName: ptr.To("configmap")},
+			}
+			fluxConfig.AdditionalConfigMapResources = []AdditionalResource{
+				{Name: "configmap"},
+				{Name: "configmap", TargetName: ptr.To("secret")},
+				{Name: "shoot-info-configmap", TargetName: ptr.To("other")},
+			}
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+		It("should forbid the reserved shoot-info names", func() {
+			fluxConfig.AdditionalSecretResources = []AdditionalResource{
+				{Name: "shoot-info-secret"},
+				{Name: "secret", TargetName: ptr.To("shoot-info"), TargetNamespace: ptr.To("app")},
+			}
+			fluxConfig.AdditionalConfigMapResources = []AdditionalResource{
+				{Name: "shoot-info-configmap"},
+				{Name: "configmap", TargetName: ptr.To("shoot-info"), TargetNamespace: ptr.To("app")},
+			}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalSecretResources[0].name"),
+					"BadValue": Equal("shoot-info"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalSecretResources[1].targetName"),
+					"BadValue": Equal("shoot-info"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalConfigMapResources[0].name"),
+					"BadValue": Equal("shoot-info"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeInvalid),
+					"Field":    Equal("root.additionalConfigMapResources[1].targetName"),
+					"BadValue": Equal("shoot-info"),
+				})),
+			))
+		})
+		It("should forbid duplicate targets", func() {
+			fluxConfig.AdditionalSecretResources = []AdditionalResource{
+				{Name: "secret"},
+				{Name: "secret", TargetNamespace: ptr.To("flux-system")},
+				{Name: "shoot-info-secret", TargetName: ptr.To("secret")},
+			}
+			fluxConfig.AdditionalConfigMapResources = []AdditionalResource{
+				{Name: "configmap", TargetNamespace: ptr.To("app")},
+				{Name: "configmap", TargetName: ptr.To("configmap"), TargetNamespace: ptr.To("app")},
+			}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeDuplicate),
+					"Field":    Equal("root.additionalSecretResources[1]"),
+					"BadValue": Equal("flux-system/secret"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeDuplicate),
+					"Field":    Equal("root.additionalSecretResources[2]"),
+					"BadValue": Equal("flux-system/secret"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":     Equal(field.ErrorTypeDuplicate),
+					"Field":    Equal("root.additionalConfigMapResources[1]"),
+					"BadValue": Equal("app/configmap"),
+				})),
+			))
+		})
+	})
+	Describe("additionalConfigMapResources validation", func() {
+		It("should allow specifying nothing", func() {
+			fluxConfig.AdditionalConfigMapResources = nil
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+		It("should find all errors", func() {
+			fluxConfig.AdditionalConfigMapResources = []AdditionalResource{
+				{Name: "valid"},
+				{Name: "wrong-kind"},
+				{Name: "no-ref"},
+				{Name: "valid", TargetName: ptr.To("invalid-name-")},
+			}
+			shoot.Spec.Resources = []gardencorev1beta1.NamedResourceReference{
+				{
+					Name: "valid",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "ConfigMap",
+					},
+				},
+				{
+					Name: "wrong-kind",
+					ResourceRef: autoscalingv1.CrossVersionObjectReference{
+						Kind: "Secret",
+					},
+				},
+			}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("root.additionalConfigMapResources[1].name"),
+					"Detail": ContainSubstring("is not a configmap"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("root.additionalConfigMapResources[2].name"),
+					"Detail": ContainSubstring("does not match any of the resource names"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(field.ErrorTypeInvalid),
+					"Field":  Equal("root.additionalConfigMapResources[3].targetName"),
+					"Detail": ContainSubstring("must be a valid resource name"),
+				})),
+			))
+		})
+	})
+	Describe("shootInfo validation", func() {
+		It("should allow valid data keys and prefixes", func() {
+			fluxConfig.ShootInfo = &ShootInfo{
+				Data: map[string]string{
+					"ENVIRONMENT": "production",
+					"_private":    "value",
+				},
+				LabelPrefixes:      []string{"example.com/"},
+				AnnotationPrefixes: []string{"example.com/"},
+				Extensions: []ShootInfoExtension{
+					{Type: "shoot-dns-service", Fields: []string{"syncProvidersFromShootSpecDNS"}},
+					{Type: "shoot-cert-service", Fields: []string{"shootIssuers.enabled"}},
+				},
+				Namespaces: []string{"tenant-a", "tenant-b"},
+				Secret: &ShootInfoSecret{
+					Keys: []string{"SHOOT_INFO_API_SERVER_URL", "SHOOT_INFO_CA_BUNDLE", "SHOOT_INFO_INGRESS_DOMAIN"},
+				},
+			}
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+		It("should require at least one secret key", func() {
+			fluxConfig.ShootInfo = &ShootInfo{Secret: &ShootInfoSecret{}}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeRequired),
+				"Field": Equal("root.shootInfo.secret.keys"),
+			}))))
+		})
+		It("should find all errors", func() {
+			fluxConfig.ShootInfo = &ShootInfo{
+				Data: map[string]string{
+					"cost-center": "1234",
+					"1ST":         "value",
+				},
+				LabelPrefixes:      []string{""},
+				AnnotationPrefixes: []string{""},
+				Extensions: []ShootInfoExtension{
+					{Type: "", Fields: []string{"valid"}},
+					{Type: "shoot-dns-service"},
+					{Type: "shoot-dns-service", Fields: []string{"spec..mode"}},
+				},
+				Namespaces: []string{"Invalid_Namespace", "tenant-a", "tenant-a"},
+				Secret: &ShootInfoSecret{
+					Keys: []string{"SHOOT_INFO_NAME"},
+				},
+			}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.shootInfo.data[cost-center]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.shootInfo.data[1ST]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.shootInfo.labelPrefixes[0]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.shootInfo.annotationPrefixes[0]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.shootInfo.extensions[0].type"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeRequired),
+					"Field": Equal("root.shootInfo.extensions[1].fields"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("root.shootInfo.extensions[2].type"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.shootInfo.extensions[2].fields[0]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.shootInfo.namespaces[0]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeDuplicate),
+					"Field": Equal("root.shootInfo.namespaces[2]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeNotSupported),
+					"Field": Equal("root.shootInfo.secret.keys[0]"),
+				})),
+			))
+		})
+	})
+
+	Describe("bootstrap validation", func() {
+		It("should allow positive durations", func() {
+			fluxConfig.Bootstrap = &Bootstrap{
+				PollInterval:         &metav1.Duration{Duration: 10 * time.Second},
+				InstallTimeout:       &metav1.Duration{Duration: 2 * time.Minute},
+				SourceTimeout:        &metav1.Duration{Duration: 15 * time.Minute},
+				KustomizationTimeout: &metav1.Duration{Duration: 15 * time.Minute},
+			}
+			Expect(ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath)).To(BeEmpty())
+		})
+		It("should find all errors", func() {
+			fluxConfig.Bootstrap = &Bootstrap{
+				PollInterval:         &metav1.Duration{},
+				InstallTimeout:       &metav1.Duration{Duration: -time.Minute},
+				SourceTimeout:        &metav1.Duration{},
+				KustomizationTimeout: &metav1.Duration{},
+			}
+			Expect(
+				ValidateFluxConfig(fluxConfig, shoot, versions, rootFldPath),
+			).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.bootstrap.pollInterval"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.bootstrap.installTimeout"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.bootstrap.sourceTimeout"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  Equal(field.ErrorTypeInvalid),
+					"Field": Equal("root.bootstrap.kustomizationTimeout"),
+				})),
+			))
+		})
+	})
 })
 
 func encodeSourceTemplate(obj runtime.Object) *runtime.RawExtension {
@@ -2,8 +2,13 @@ package extension
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"time"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
 	fluxinstall "github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
 	fluxmeta "github.com/fluxcd/pkg/apis/meta"
@@ -14,9 +19,11 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/util"
 	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/client/kubernetes"
 	"github.com/gardener/gardener/pkg/extensions"
+	gardenerutils "github.com/gardener/gardener/pkg/utils/gardener"
 	"github.com/gardener/gardener/pkg/utils/kubernetes/health"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -25,30 +32,57 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1/validation"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
 type actuator struct {
-	client  client.Client
-	decoder runtime.Decoder
+	client   client.Client
+	decoder  runtime.Decoder
+	recorder events.EventRecorder
 
 	gardenClusterIdentity string
+	bootstrapOptions      BootstrapOptions
+	manifests             *fluxmanifests.Source
+	manifestCache         *fluxmanifests.Cache
+	imageDigests          fluxmanifests.DigestCatalog
+	versions              *fluxversions.Catalog
 }
 
 // NewActuator returns an actuator responsible for Extension resources.
-func NewActuator(client client.Client, gardenClusterIdentity string) extension.Actuator {
+func NewActuator(
+	client client.Client,
+	recorder events.EventRecorder,
+	gardenClusterIdentity string,
+	bootstrapOptions BootstrapOptions,
+	manifests *fluxmanifests.Source,
+	manifestCache *fluxmanifests.Cache,
+	imageDigests fluxmanifests.DigestCatalog,
+	versions *fluxversions.Catalog,
+) extension.Actuator {
 	return &actuator{
 		client:                client,
 		decoder:               serializer.NewCodecFactory(client.Scheme()).UniversalDecoder(),
+		recorder:              recorder,
 		gardenClusterIdentity: gardenClusterIdentity,
+		bootstrapOptions:      bootstrapOptions,
+		manifests:             manifests,
+		manifestCache:         manifestCache,
+		imageDigests:          imageDigests,
+		versions:              versions,
 	}
 }
 
@@ -60,8 +94,8 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, ext *extensio
 	}
 
 	if extensionscontroller.IsHibernationEnabled(cluster) {
-		// when hibernation is enabled there is nothing for us to do
-		return nil
+		// when hibernation is enabled there is nothing for us to do except remembering it for the wake-up
+		return a.recordHibernation(ctx, ext)
 	}
 
 	config, err := a.DecodeProviderConfig(ext.Spec.ProviderConfig)
@@ -70,7 +104,9 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, ext *extensio
 	}
 
 	// TODO: add an admission component that validates the providerConfig when creating/updating Shoots
-	if allErrs := validation.ValidateFluxConfig(config, cluster.Shoot, nil); len(allErrs) > 0 {
+	// The Flux version is only checked against the catalog of supported versions before installing Flux. Once Flux has
+	// been bootstrapped, the version is not used anymore and shoots must not break when the catalog changes.
+	if allErrs := validation.ValidateFluxConfig(config, cluster.Shoot, nil, nil); len(allErrs) > 0 {
 		return fmt.Errorf("invalid providerConfig: %w", allErrs.ToAggregate())
 	}
 
@@ -79,54 +115,91 @@ func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, ext *extensio
 		return fmt.Errorf("error creating shoot client: %w", err)
 	}
 
+	recorder := NewEventRecorder(a.recorder, ext)
+
+	if ptr.Deref(config.DryRun, false) {
+		if err := a.validateInstallation(config.Flux); err != nil {
+			return err
+		}
+		return a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)
+	}
+
+	if err := a.handleRebootstrap(ctx, log, recorder, ext, cluster.Shoot); err != nil {
+		return fmt.Errorf("error handling rebootstrap request: %w", err)
+	}
+
+	if err := a.handleWakeUp(ctx, log, recorder, shootClient, ext, config); err != nil {
+		return err
+	}
+
 	if IsFluxBootstrapped(ext) {
 		log.V(1).Info("Flux installation has been bootstrapped already, will only reconcile secrets")
 
-		if err := ReconcileSecrets(ctx, log, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+		if err := ReconcileSecrets(ctx, log, recorder, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
 			return fmt.Errorf("error reconciling secrets: %w", err)
 		}
 
+		if err := ReconcileConfigMaps(ctx, log, recorder, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+			return fmt.Errorf("error reconciling ConfigMaps: %w", err)
+		}
+
 		if err := ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, a.gardenClusterIdentity); err != nil {
 			return fmt.Errorf("error reconciling ConfigMap %q: %w", shootInfoConfigMapName, err)
 		}
 
-		return nil
-	}
+		if err := ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster); err != nil {
+			return fmt.Errorf("error reconciling Secret %q: %w", shootInfoSecretName, err)
+		}
 
-	if err := InstallFlux(ctx, log, shootClient, config.Flux); err != nil {
-		return fmt.Errorf("error installing Flux: %w", err)
-	}
+		if err := a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config); err != nil {
+			return fmt.Errorf("error reconciling suspension of Flux objects: %w", err)
+		}
 
-	// secrets might be necessary for the source to get ready
-	if err := ReconcileSecrets(ctx, log, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
-		return fmt.Errorf("error reconciling secrets: %w", err)
+		return a.reportReadiness(ctx, recorder, shootClient, ext, config)
 	}
 
-	if config.Source != nil {
-		if err := BootstrapSource(ctx, log, shootClient, config.Source); err != nil {
-			return fmt.Errorf("error bootstrappping Flux GitRepository: %w", err)
-		}
+	if err := a.validateInstallation(config.Flux); err != nil {
+		return err
 	}
 
-	// configMap might be necessary for the kustomization to get ready
-	if err := ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, a.gardenClusterIdentity); err != nil {
-		return fmt.Errorf("error reconciling ConfigMap %q: %w", shootInfoConfigMapName, err)
+	return a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)
+}
+
+// validateInstallation resolves the configured Flux version against the catalog of supported versions and checks that
+// the digest catalog contains the digests of all images if pinImageDigests is set. It is only called before installing
+// Flux, so that shoots with a bootstrapped Flux installation are not affected by changes of the catalogs.
+func (a *actuator) validateInstallation(config *fluxv1alpha1.FluxInstallation) error {
+	if err := ResolveVersion(a.versions, config); err != nil {
+		return err
 	}
 
-	if config.Kustomization != nil {
-		if err := BootstrapKustomization(ctx, log, shootClient, config.Kustomization); err != nil {
-			return fmt.Errorf("error bootstrappping Flux Kustomization: %w", err)
-		}
+	if !ptr.Deref(config.PinImageDigests, false) {
+		return nil
+	}
+	// other errors are reported when installing Flux
+	if _, err := BuildInstallManifest(config, a.manifests, a.manifestCache, a.imageDigests); errors.Is(err, ErrMissingImageDigest) {
+		fieldErr := field.Invalid(field.NewPath("flux", "pinImageDigests"), true, fmt.Sprintf("digests of Flux %s are not available: %s", ptr.Deref(config.Version, ""), err))
+		return v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid providerConfig: %w", fieldErr), gardencorev1beta1.ErrorConfigurationProblem)
 	}
+	return nil
+}
 
-	if err := SetFluxBootstrapped(ctx, a.client, ext); err != nil {
-		return fmt.Errorf("error marking successful boostrapping: %w", err)
+// ResolveVersion replaces a semver constraint in the given configuration with the newest version in the given catalog
+// matching it. If versions is nil, the configured version is kept.
+func ResolveVersion(versions *fluxversions.Catalog, config *fluxv1alpha1.FluxInstallation) error {
+	if versions == nil || config.Version == nil {
+		return nil
 	}
 
+	version, err := versions.Resolve(*config.Version)
+	if err != nil {
+		return fmt.Errorf("error resolving Flux version: %w", err)
+	}
+	config.Version = &version
 	return nil
 }
 
-// Delete does nothing. The extension purposely does not perform deletion of the deployed Flux components or resources
+// Delete doesn't touch the shoot. The extension purposely does not perform deletion of the deployed Flux components or resources
 // because it will most likely be a destructive operation. If users want to uninstall flux, they should use the
 // documented approaches. On Shoot deletion, the objects will be cleaned up anyway, there is no point in deleting them
 // gracefully.
@@ -151,7 +224,8 @@ func (a *actuator) Restore(context.Context, logr.Logger, *extensionsv1alpha1.Ext
 
 // ReconcileShootInfoConfigMap creates or updates a ConfigMap in the specified Flux namespace in the shoot cluster.
 // The ConfigMap contains information about the Shoot cluster, such as its technical ID which can be used for
-// substitutions in flux kustomizations or helmreleases.
+// substitutions in flux kustomizations or helmreleases. The ConfigMap is replicated to the namespaces configured in
+// shootInfo.namespaces and deleted from namespaces that are no longer configured.
 func ReconcileShootInfoConfigMap(
 	ctx context.Context,
 	log logr.Logger,
@@ -160,50 +234,356 @@ func ReconcileShootInfoConfigMap(
 	cluster *extensions.Cluster,
 	gardenClusterIdentity string,
 ) error {
-	configMap := &corev1.ConfigMap{
+	data := shootInfoData(cluster, fluxCfg.ShootInfo, gardenClusterIdentity)
+
+	namespaces := shootInfoNamespaces(fluxCfg)
+	for _, namespace := range sets.List(namespaces) {
+		if namespace != *fluxCfg.Flux.Namespace {
+			if err := ensureNamespace(ctx, shootClient, namespace); err != nil {
+				return err
+			}
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      shootInfoConfigMapName,
+				Namespace: namespace,
+			},
+		}
+
+		result, err := controllerutil.CreateOrUpdate(ctx, shootClient, configMap, func() error {
+			configMap.Labels = map[string]string{
+				managedByLabelKey: managedByLabelValue,
+			}
+			configMap.Data = maps.Clone(data)
+			return nil
+		})
+
+		if err != nil {
+			return fmt.Errorf("failed to create or update ConfigMap %q: %w", client.ObjectKeyFromObject(configMap), err)
+		}
+
+		if result != controllerutil.OperationResultNone {
+			log.Info("Synced shoot info ConfigMap", "configMap", client.ObjectKeyFromObject(configMap))
+		}
+	}
+
+	// cleanup ConfigMaps in namespaces that are no longer configured
+	configMapList := &corev1.ConfigMapList{}
+	if err := shootClient.List(ctx, configMapList,
+		client.MatchingLabels{managedByLabelKey: managedByLabelValue},
+	); err != nil {
+		return fmt.Errorf("failed to list managed ConfigMaps in shoot: %w", err)
+	}
+	for _, configMap := range configMapList.Items {
+		if configMap.Name != shootInfoConfigMapName || namespaces.Has(configMap.Namespace) {
+			continue
+		}
+		if err := shootClient.Delete(ctx, &configMap); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ConfigMap %q: %w", client.ObjectKeyFromObject(&configMap), err)
+		}
+		log.Info("Deleted shoot info ConfigMap from namespace that is no longer configured", "configMap", client.ObjectKeyFromObject(&configMap))
+	}
+
+	return nil
+}
+
+// shootInfoNamespaces returns the namespaces in the shoot the shoot-info ConfigMap is written to: the Flux namespace
+// and the additional namespaces configured in ShootInfo.Namespaces.
+func shootInfoNamespaces(fluxCfg *fluxv1alpha1.FluxConfig) sets.Set[string] {
+	namespaces := sets.New(*fluxCfg.Flux.Namespace)
+	if fluxCfg.ShootInfo != nil {
+		namespaces.Insert(fluxCfg.ShootInfo.Namespaces...)
+	}
+	return namespaces
+}
+
+// shootInfoData returns the data of the shoot-info ConfigMap. Keys for optional Shoot fields are only added if the
+// field is set. Keys generated from the Shoot take precedence over the user-defined data and projected metadata.
+func shootInfoData(cluster *extensions.Cluster, shootInfo *fluxv1alpha1.ShootInfo, gardenClusterIdentity string) map[string]string {
+	shoot := cluster.Shoot
+	data := map[string]string{}
+	if shootInfo != nil {
+		maps.Copy(data, shootInfo.Data)
+		projectMetadata(data, "SHOOT_INFO_LABEL_", shoot.Labels, shootInfo.LabelPrefixes)
+		projectMetadata(data, "SHOOT_INFO_ANNOTATION_", shoot.Annotations, shootInfo.AnnotationPrefixes)
+	}
+
+	maps.Copy(data, map[string]string{
+		"SHOOT_INFO_CLUSTER_IDENTITY":        *shoot.Status.ClusterIdentity,
+		"SHOOT_INFO_NAME":                    shoot.Name,
+		"SHOOT_INFO_TECHNICAL_ID":            shoot.Status.TechnicalID,
+		"SHOOT_INFO_GARDEN_CLUSTER_IDENTITY": gardenClusterIdentity,
+		"SHOOT_INFO_REGION":                  shoot.Spec.Region,
+		"SHOOT_INFO_PROVIDER_TYPE":           shoot.Spec.Provider.Type,
+		"SHOOT_INFO_KUBERNETES_VERSION":      shoot.Spec.Kubernetes.Version,
+	})
+
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+
+	setIfNotEmpty("SHOOT_INFO_PROJECT_NAME", projectNameFromTechnicalID(shoot.Status.TechnicalID, shoot.Name))
+	seedName := ptr.Deref(shoot.Spec.SeedName, "")
+	if seedName == "" && cluster.Seed != nil {
+		seedName = cluster.Seed.Name
+	}
+	setIfNotEmpty("SHOOT_INFO_SEED_NAME", seedName)
+	setIfNotEmpty("SHOOT_INFO_PURPOSE", string(ptr.Deref(shoot.Spec.Purpose, "")))
+	if shoot.Spec.DNS != nil {
+		setIfNotEmpty("SHOOT_INFO_DOMAIN", ptr.Deref(shoot.Spec.DNS.Domain, ""))
+	}
+	if networking := shoot.Spec.Networking; networking != nil {
+		setIfNotEmpty("SHOOT_INFO_POD_CIDR", ptr.Deref(networking.Pods, ""))
+		setIfNotEmpty("SHOOT_INFO_SERVICE_CIDR", ptr.Deref(networking.Services, ""))
+		setIfNotEmpty("SHOOT_INFO_NODE_CIDR", ptr.Deref(networking.Nodes, ""))
+	}
+
+	var workerPools []string
+	zones := sets.New[string]()
+	for _, worker := range shoot.Spec.Provider.Workers {
+		workerPools = append(workerPools, worker.Name)
+		zones.Insert(worker.Zones...)
+	}
+	setIfNotEmpty("SHOOT_INFO_WORKER_POOLS", strings.Join(workerPools, ","))
+	setIfNotEmpty("SHOOT_INFO_WORKER_ZONES", strings.Join(sets.List(zones), ","))
+
+	var selectedExtensions []fluxv1alpha1.ShootInfoExtension
+	if shootInfo != nil {
+		selectedExtensions = shootInfo.Extensions
+	}
+	addExtensionsData(data, shoot.Spec.Extensions, selectedExtensions)
+
+	return data
+}
+
+// addExtensionsData adds the types of all extensions enabled on the Shoot to data, as well as the selected fields of
+// their providerConfigs. Fields that are not present in the providerConfig are skipped.
+func addExtensionsData(data map[string]string, shootExtensions []gardencorev1beta1.Extension, selected []fluxv1alpha1.ShootInfoExtension) {
+	enabled := map[string]gardencorev1beta1.Extension{}
+	for _, ext := range shootExtensions {
+		if ptr.Deref(ext.Disabled, false) {
+			continue
+		}
+		enabled[ext.Type] = ext
+		data["SHOOT_INFO_EXTENSION_"+normaliseVariableName(ext.Type)+"_ENABLED"] = "true"
+	}
+	if len(enabled) > 0 {
+		data["SHOOT_INFO_EXTENSIONS"] = strings.Join(slices.Sorted(maps.Keys(enabled)), ",")
+	}
+
+	for _, selection := range selected {
+		ext, ok := enabled[selection.Type]
+		if !ok || ext.ProviderConfig == nil || len(ext.ProviderConfig.Raw) == 0 {
+			continue
+		}
+
+		providerConfig := map[string]any{}
+		if err := json.Unmarshal(ext.ProviderConfig.Raw, &providerConfig); err != nil {
+			continue
+		}
+
+		for _, fieldPath := range selection.Fields {
+			value, found, err := unstructured.NestedFieldNoCopy(providerConfig, strings.Split(fieldPath, ".")...)
+			if err != nil || !found || value == nil {
+				continue
+			}
+
+			key := "SHOOT_INFO_EXTENSION_" + normaliseVariableName(selection.Type) + "_" + normaliseVariableName(fieldPath)
+			switch v := value.(type) {
+			case string:
+				data[key] = v
+			case bool, float64, int64:
+				data[key] = fmt.Sprint(v)
+			default:
+				if encoded, err := json.Marshal(v); err == nil {
+					data[key] = string(encoded)
+				}
+			}
+		}
+	}
+}
+
+// ReconcileShootInfoSecret creates or updates a Secret in the specified Flux namespace in the shoot cluster if it is
+// configured in shootInfo.secret. The Secret contains sensitive information about the Shoot cluster, which should not
+// be published in the shoot-info ConfigMap. Only the selected keys are added. If the Secret is not configured, a
+// Secret previously created by the extension is deleted.
+func ReconcileShootInfoSecret(
+	ctx context.Context,
+	log logr.Logger,
+	shootClient client.Client,
+	fluxCfg *fluxv1alpha1.FluxConfig,
+	cluster *extensions.Cluster,
+) error {
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      shootInfoConfigMapName,
+			Name:      shootInfoSecretName,
 			Namespace: *fluxCfg.Flux.Namespace,
 		},
 	}
 
-	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, configMap, func() error {
-		configMap.Labels = map[string]string{
-			managedByLabelKey: managedByLabelValue,
+	if fluxCfg.ShootInfo == nil || fluxCfg.ShootInfo.Secret == nil {
+		if err := shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read Secret %q: %w", client.ObjectKeyFromObject(secret), err)
 		}
-		configMap.Data = map[string]string{
-			"SHOOT_INFO_CLUSTER_IDENTITY":        *cluster.Shoot.Status.ClusterIdentity,
-			"SHOOT_INFO_NAME":                    cluster.Shoot.Name,
-			"SHOOT_INFO_TECHNICAL_ID":            cluster.Shoot.Status.TechnicalID,
-			"SHOOT_INFO_GARDEN_CLUSTER_IDENTITY": gardenClusterIdentity,
+
+		// don't delete a Secret with the same name that is not managed by the extension
+		if secret.Labels[managedByLabelKey] != managedByLabelValue {
+			return nil
+		}
+
+		if err := shootClient.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete Secret %q: %w", client.ObjectKeyFromObject(secret), err)
 		}
+		log.Info("Deleted shoot info Secret", "secret", client.ObjectKeyFromObject(secret))
 		return nil
-	})
+	}
 
+	data, err := shootInfoSecretData(ctx, shootClient, cluster, *fluxCfg.Flux.Namespace, fluxCfg.ShootInfo.Secret.Keys)
 	if err != nil {
-		return fmt.Errorf("failed to create or update ConfigMap %q: %w", client.ObjectKeyFromObject(configMap), err)
+		return err
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, secret, func() error {
+		secret.Labels = map[string]string{
+			managedByLabelKey: managedByLabelValue,
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update Secret %q: %w", client.ObjectKeyFromObject(secret), err)
 	}
 
 	if result != controllerutil.OperationResultNone {
-		log.Info("Synced shoot info ConfigMap", "configMap", client.ObjectKeyFromObject(configMap))
+		log.Info("Synced shoot info Secret", "secret", client.ObjectKeyFromObject(secret))
 	}
 
 	return nil
 }
 
+// shootInfoSecretData returns the data of the shoot-info Secret containing the given keys. Keys without a value, e.g.,
+// the ingress domain of a Shoot without DNS domain, are skipped.
+func shootInfoSecretData(ctx context.Context, shootClient client.Client, cluster *extensions.Cluster, namespace string, keys []string) (map[string][]byte, error) {
+	shoot := cluster.Shoot
+	data := map[string][]byte{}
+
+	for _, key := range keys {
+		var value string
+		switch key {
+		case fluxv1alpha1.ShootInfoSecretKeyAPIServerURL:
+			value = apiServerURL(shoot)
+		case fluxv1alpha1.ShootInfoSecretKeyCABundle:
+			// kube-controller-manager publishes the cluster's CA bundle in every namespace
+			rootCA := &corev1.ConfigMap{}
+			if err := shootClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "kube-root-ca.crt"}, rootCA); err != nil {
+				return nil, fmt.Errorf("error reading CA bundle: %w", err)
+			}
+			value = rootCA.Data["ca.crt"]
+		case fluxv1alpha1.ShootInfoSecretKeyIngressDomain:
+			if shoot.Spec.DNS != nil && ptr.Deref(shoot.Spec.DNS.Domain, "") != "" {
+				value = "*." + gardenerutils.IngressPrefix + "." + *shoot.Spec.DNS.Domain
+			}
+		}
+
+		if value != "" {
+			data[key] = []byte(value)
+		}
+	}
+
+	return data, nil
+}
+
+// apiServerURL returns the external URL of the Shoot's API server. If the Shoot doesn't advertise an external address
+// yet, the URL is derived from the Shoot's DNS domain.
+func apiServerURL(shoot *gardencorev1beta1.Shoot) string {
+	for _, address := range shoot.Status.AdvertisedAddresses {
+		if address.Name == v1beta1constants.AdvertisedAddressExternal {
+			return address.URL
+		}
+	}
+	if shoot.Spec.DNS != nil && ptr.Deref(shoot.Spec.DNS.Domain, "") != "" {
+		return "https://" + v1beta1constants.APIServerFQDNPrefix + "." + *shoot.Spec.DNS.Domain
+	}
+	return ""
+}
+
+// projectMetadata adds all metadata entries with one of the given prefixes to data. The keys are normalised to valid
+// Flux variable names and prefixed with keyPrefix. Entries are processed in sorted order, so that the result is stable
+// if multiple keys are normalised to the same variable name.
+func projectMetadata(data map[string]string, keyPrefix string, metadata map[string]string, prefixes []string) {
+	if len(prefixes) == 0 {
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		if !slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+			continue
+		}
+		data[keyPrefix+normaliseVariableName(key)] = metadata[key]
+	}
+}
+
+// normaliseVariableName converts the given key to upper case and replaces all characters that are not allowed in Flux
+// variable names with underscores.
+func normaliseVariableName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(key))
+}
+
+// projectNameFromTechnicalID extracts the project name from a technical ID of the form "shoot--<project>--<name>".
+// It returns an empty string if the technical ID doesn't have this form.
+func projectNameFromTechnicalID(technicalID, shootName string) string {
+	prefix := v1beta1constants.TechnicalIDPrefix + "-"
+	suffix := "--" + shootName
+	if !strings.HasPrefix(technicalID, prefix) || !strings.HasSuffix(technicalID, suffix) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(technicalID, prefix), suffix)
+}
+
 // DecodeProviderConfig decodes the given providerConfig and performs API defaulting. If the providerConfig is empty,
 // a new empty FluxConfig object is defaulted instead. This simplifies the controller's code as we can assume that all
 // fields have been defaulted.
 func (a *actuator) DecodeProviderConfig(rawExtension *runtime.RawExtension) (*fluxv1alpha1.FluxConfig, error) {
+	return DecodeProviderConfig(a.client.Scheme(), a.decoder, rawExtension)
+}
+
+// DecodeProviderConfig decodes the given providerConfig with the given decoder and performs API defaulting with the
+// given scheme, see actuator.DecodeProviderConfig.
+func DecodeProviderConfig(scheme *runtime.Scheme, decoder runtime.Decoder, rawExtension *runtime.RawExtension) (*fluxv1alpha1.FluxConfig, error) {
 	config := &fluxv1alpha1.FluxConfig{}
 	if rawExtension == nil || rawExtension.Raw == nil {
-		a.client.Scheme().Default(config)
-	} else if err := runtime.DecodeInto(a.decoder, rawExtension.Raw, config); err != nil {
+		scheme.Default(config)
+	} else if err := runtime.DecodeInto(decoder, rawExtension.Raw, config); err != nil {
 		return nil, err
 	}
 	return config, nil
 }
 
+// DecodeProviderStatus decodes the given providerStatus. If the providerStatus is empty, a new empty FluxStatus object
+// is returned.
+func (a *actuator) DecodeProviderStatus(rawExtension *runtime.RawExtension) (*fluxv1alpha1.FluxStatus, error) {
+	status := &fluxv1alpha1.FluxStatus{}
+	if rawExtension == nil || rawExtension.Raw == nil {
+		return status, nil
+	}
+	if err := runtime.DecodeInto(a.decoder, rawExtension.Raw, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
 // IsFluxBootstrapped checks whether Flux was bootstrapped successfully at least once by checking the bootstrapped
 // condition in the Extension status.
 func IsFluxBootstrapped(ext *extensionsv1alpha1.Extension) bool {
@@ -238,70 +618,124 @@ func SetFluxBootstrapped(ctx context.Context, c client.Client, ext *extensionsv1
 	return nil
 }
 
-// InstallFlux applies the Flux install manifest based on the given configuration. It also performs a basic health check
-// before returning.
-func InstallFlux(ctx context.Context, log logr.Logger, c client.Client, config *fluxv1alpha1.FluxInstallation) error {
-	return installFlux(ctx, log, c, config, "", 5*time.Second, time.Minute)
-}
-
-func installFlux(
+// InstallFlux applies the Flux install manifest based on the given configuration. It doesn't wait for the installation
+// to get ready, use CheckFluxInstallation for this.
+func InstallFlux(
 	ctx context.Context,
 	log logr.Logger,
 	c client.Client,
 	config *fluxv1alpha1.FluxInstallation,
-	manifestsBase string,
-	interval time.Duration,
-	timeout time.Duration,
+	manifests *fluxmanifests.Source,
+	cache *fluxmanifests.Cache,
+	imageDigests fluxmanifests.DigestCatalog,
 ) error {
-	log = log.WithValues("version", config.Version)
-	log.Info("Installing Flux")
+	log.Info("Installing Flux", "version", config.Version)
 
-	installManifest, err := GenerateInstallManifest(config, manifestsBase)
+	installManifest, err := BuildInstallManifest(config, manifests, cache, imageDigests)
 	if err != nil {
-		return fmt.Errorf("error generating install manifest: %w", err)
+		return err
 	}
 
 	if err := kubernetes.NewApplier(c, c.RESTMapper()).ApplyManifest(ctx, kubernetes.NewManifestReader(installManifest), nil); err != nil {
 		return fmt.Errorf("error applying Flux install manifest: %w", err)
 	}
 
-	log.Info("Waiting for Flux installation to get ready")
-	// Wait for GitRepository CRD to become healthy as a basic indicator of whether the installation is ready to be
-	// bootstrapped.
+	return nil
+}
+
+// CheckFluxInstallation performs a basic health check of the Flux installation. It returns an error if the
+// installation is not ready yet.
+func CheckFluxInstallation(ctx context.Context, c client.Reader, config *fluxv1alpha1.FluxInstallation) error {
+	// Check the GitRepository CRD as a basic indicator of whether the installation is ready to be bootstrapped.
 	// We don't intend to health check the entire Flux installation, but we want to avoid bootstrap failures that could
 	// have been avoided by a short wait.
 	gitRepositoryCRD := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{Name: "gitrepositories." + sourcev1.GroupVersion.Group},
 	}
-	if err := WaitForObject(ctx, c, gitRepositoryCRD, interval, timeout, func() (done bool, err error) {
+	if _, err := CheckObject(ctx, c, gitRepositoryCRD, func() (done bool, err error) {
 		err = health.CheckCustomResourceDefinition(gitRepositoryCRD)
 		return err == nil, err
 	}); err != nil {
-		return fmt.Errorf("error waiting for Flux installation to get ready: %w", err)
+		return err
 	}
 
-	// Wait for one of the deployments to be available to ensure the selected registry actually hosts flux container
-	// images.
+	// Check one of the deployments to ensure the selected registry actually hosts flux container images.
 	sourceController := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "source-controller",
 			Namespace: *config.Namespace,
 		},
 	}
-	if err := WaitForObject(ctx, c, sourceController, interval, timeout, func() (done bool, err error) {
+	if _, err := CheckObject(ctx, c, sourceController, func() (done bool, err error) {
 		err = health.CheckDeployment(sourceController)
 		return err == nil, err
 	}); err != nil {
-		return fmt.Errorf("error waiting for Flux installation to get ready: %w", err)
+		// report image pull problems, e.g., if the selected registry doesn't host the flux container images
+		if imagePullErr := checkImagePull(ctx, c, sourceController); imagePullErr != nil {
+			return fmt.Errorf("%w: %w", err, imagePullErr)
+		}
+		return err
 	}
 
-	log.Info("Successfully installed Flux")
-
 	return nil
 }
 
+// BuildInstallManifest returns the Flux install manifest that InstallFlux applies for the given configuration, i.e.,
+// the cached install manifest with the configured image overrides and digests.
+func BuildInstallManifest(
+	config *fluxv1alpha1.FluxInstallation,
+	manifests *fluxmanifests.Source,
+	cache *fluxmanifests.Cache,
+	imageDigests fluxmanifests.DigestCatalog,
+) ([]byte, error) {
+	installManifest, err := GenerateInstallManifestCached(config, manifests, cache)
+	if err != nil {
+		return nil, fmt.Errorf("error generating install manifest: %w", err)
+	}
+	if installManifest, err = RewriteImages(installManifest, config, imageDigests); err != nil {
+		return nil, fmt.Errorf("error rewriting images in install manifest: %w", err)
+	}
+	return installManifest, nil
+}
+
+// GenerateInstallManifestCached returns the Flux install manifest from the given cache, keyed by the install options and
+// the manifest source. On a cache miss, the manifest is generated with GenerateInstallManifestFromSource. The returned
+// manifest must not be modified.
+func GenerateInstallManifestCached(config *fluxv1alpha1.FluxInstallation, manifests *fluxmanifests.Source, cache *fluxmanifests.Cache) ([]byte, error) {
+	key, err := fluxmanifests.CacheKey(buildFluxInstallOptions(config), manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.Get(key, func() ([]byte, error) {
+		return GenerateInstallManifestFromSource(config, manifests)
+	})
+}
+
+// GenerateInstallManifestFromSource generates the Flux install manifest from the given local manifests. If no local
+// manifests are available, the manifests are downloaded from GitHub.
+func GenerateInstallManifestFromSource(config *fluxv1alpha1.FluxInstallation, manifests *fluxmanifests.Source) ([]byte, error) {
+	if !manifests.IsLocal() {
+		return GenerateInstallManifest(config, "")
+	}
+
+	// fluxinstall.Generate writes its kustomization into manifestsBase, so copy the manifests to a separate directory
+	// for every invocation
+	manifestsBase, err := os.MkdirTemp("", "flux-manifests-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(manifestsBase)
+
+	if err := manifests.CopyTo(*config.Version, manifestsBase); err != nil {
+		return nil, err
+	}
+
+	return GenerateInstallManifest(config, manifestsBase)
+}
+
 // GenerateInstallManifest generates the Flux install manifest based on the given configuration just like
-// "flux install --export". manifestsBase can be set for tests.
+// "flux install --export". If manifestsBase is empty, the manifests are downloaded from GitHub.
 func GenerateInstallManifest(config *fluxv1alpha1.FluxInstallation, manifestsBase string) ([]byte, error) {
 	options := buildFluxInstallOptions(config)
 	manifest, err := fluxinstall.Generate(options, manifestsBase)
@@ -328,48 +762,42 @@ func buildFluxInstallOptions(config *fluxv1alpha1.FluxInstallation) fluxinstall.
 	return options
 }
 
-// BootstrapSource creates the source object (GitRepository or OCIRepository) specified in the given config and waits for it to get ready.
-func BootstrapSource(
-	ctx context.Context,
-	log logr.Logger,
-	shootClient client.Client,
-	config *fluxv1alpha1.Source,
-) error {
-	return bootstrapSource(ctx, log, shootClient, config, 5*time.Second, 5*time.Minute)
+// fluxObject is a Flux object that reports its readiness in status conditions.
+type fluxObject interface {
+	client.Object
+	fluxmeta.ObjectWithConditions
 }
 
-func bootstrapSource(
+// BootstrapSource creates the source object (GitRepository or OCIRepository) specified in the given config. It doesn't
+// wait for the source to get ready, use CheckSource for this. If suspend is true, the source is applied suspended, so
+// that a suspended source is never resumed by the bootstrap, see FluxConfig.suspend.
+func BootstrapSource(
 	ctx context.Context,
 	log logr.Logger,
+	recorder *EventRecorder,
 	shootClient client.Client,
 	config *fluxv1alpha1.Source,
-	interval time.Duration,
-	timeout time.Duration,
+	suspend bool,
 ) error {
-	if config.Template == nil {
-		return fmt.Errorf("source template is required")
-	}
-
-	// Decode the template to determine source type
-	obj, _, err := fluxv1alpha1.DecodeSourceTemplate(config.Template)
+	sourceTemplate, err := decodeSourceObject(config)
 	if err != nil {
-		return fmt.Errorf("failed to decode source template: %w", err)
+		return err
 	}
 
 	// Route to appropriate bootstrap function based on decoded type
-	switch sourceTemplate := obj.(type) {
+	switch sourceTemplate := sourceTemplate.(type) {
 	case *sourcev1.GitRepository:
 		gitRepository := sourceTemplate.DeepCopy()
 		return bootstrapSourceRepository(
 			ctx,
 			log,
+			recorder,
 			shootClient,
 			gitRepository,
 			"GitRepository",
-			interval,
-			timeout,
 			func() error {
 				sourceTemplate.Spec.DeepCopyInto(&gitRepository.Spec)
+				gitRepository.Spec.Suspend = suspend || sourceTemplate.Spec.Suspend
 				return nil
 			},
 		)
@@ -378,13 +806,13 @@ func bootstrapSource(
 		return bootstrapSourceRepository(
 			ctx,
 			log,
+			recorder,
 			shootClient,
 			ociRepository,
 			"OCIRepository",
-			interval,
-			timeout,
 			func() error {
 				sourceTemplate.Spec.DeepCopyInto(&ociRepository.Spec)
+				ociRepository.Spec.Suspend = suspend || sourceTemplate.Spec.Suspend
 				return nil
 			},
 		)
@@ -393,15 +821,42 @@ func bootstrapSource(
 	}
 }
 
+// CheckSource checks whether the source object specified in the given config is ready. See CheckObject.
+func CheckSource(ctx context.Context, shootClient client.Reader, config *fluxv1alpha1.Source) (done bool, err error) {
+	source, err := decodeSourceObject(config)
+	if err != nil {
+		return true, err
+	}
+	return CheckObject(ctx, shootClient, source, CheckFluxObject(source))
+}
+
+// decodeSourceObject decodes the source template of the given config.
+func decodeSourceObject(config *fluxv1alpha1.Source) (fluxObject, error) {
+	if config.Template == nil {
+		return nil, fmt.Errorf("source template is required")
+	}
+
+	// Decode the template to determine source type
+	obj, _, err := fluxv1alpha1.DecodeSourceTemplate(config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode source template: %w", err)
+	}
+
+	source, ok := obj.(fluxObject)
+	if !ok {
+		return nil, fmt.Errorf("unsupported source type: %T", obj)
+	}
+	return source, nil
+}
+
 // bootstrapSourceRepository is a generic helper for bootstrapping Flux source repositories.
 func bootstrapSourceRepository(
 	ctx context.Context,
 	log logr.Logger,
+	recorder *EventRecorder,
 	shootClient client.Client,
 	obj client.Object,
 	resourceType string,
-	interval time.Duration,
-	timeout time.Duration,
 	mutateFn func() error,
 ) error {
 	log.Info("Bootstrapping Flux " + resourceType)
@@ -413,37 +868,21 @@ func bootstrapSourceRepository(
 	}
 
 	// Create or update the source repository
-	if _, err := controllerutil.CreateOrUpdate(ctx, shootClient, obj, mutateFn); err != nil {
+	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, obj, mutateFn)
+	if err != nil {
 		return fmt.Errorf("error applying %s template: %w", resourceType, err)
 	}
-
-	log.Info("Waiting for " + resourceType + " to get ready")
-	withMeta, ok := obj.(fluxmeta.ObjectWithConditions)
-	if !ok {
-		return fmt.Errorf("object does not implement ObjectWithConditions interface")
-	}
-	if err := WaitForObject(ctx, shootClient, obj, interval, timeout, CheckFluxObject(withMeta)); err != nil {
-		return fmt.Errorf("error waiting for %s to get ready: %w", resourceType, err)
+	if result == controllerutil.OperationResultCreated {
+		recorder.Normal(EventReasonSourceCreated, eventActionBootstrap, "Created Flux %s %s", resourceType, client.ObjectKeyFromObject(obj))
 	}
 
-	log.Info("Successfully bootstrapped Flux " + resourceType)
-
 	return nil
 }
 
-// BootstrapKustomization creates the Kustomization object specified in the given config and waits for it to get ready.
-func BootstrapKustomization(ctx context.Context, log logr.Logger, c client.Client, config *fluxv1alpha1.Kustomization) error {
-	return bootstrapKustomization(ctx, log, c, config, 5*time.Second, 5*time.Minute)
-}
-
-func bootstrapKustomization(
-	ctx context.Context,
-	log logr.Logger,
-	c client.Client,
-	config *fluxv1alpha1.Kustomization,
-	interval time.Duration,
-	timeout time.Duration,
-) error {
+// BootstrapKustomization creates the Kustomization object specified in the given config. It doesn't wait for the
+// Kustomization to get ready, use CheckKustomization for this. If suspend is true, the Kustomization is applied
+// suspended, see BootstrapSource.
+func BootstrapKustomization(ctx context.Context, log logr.Logger, recorder *EventRecorder, c client.Client, config *fluxv1alpha1.Kustomization, suspend bool) error {
 	log.Info("Bootstrapping Flux Kustomization")
 
 	// Create Namespace in case the GitRepository is located in a different namespace than the Flux components.
@@ -453,60 +892,47 @@ func bootstrapKustomization(
 	}
 
 	kustomization := config.Template.DeepCopy()
-	if _, err := controllerutil.CreateOrUpdate(ctx, c, kustomization, func() error {
+	result, err := controllerutil.CreateOrUpdate(ctx, c, kustomization, func() error {
 		config.Template.Spec.DeepCopyInto(&kustomization.Spec)
+		kustomization.Spec.Suspend = suspend || config.Template.Spec.Suspend
 		return nil
-	}); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("error applying Kustomization template: %w", err)
 	}
-
-	log.Info("Waiting for Kustomization to get ready")
-	if err := WaitForObject(ctx, c, kustomization, interval, timeout, CheckFluxObject(kustomization)); err != nil {
-		return fmt.Errorf("error waiting for Kustomization to get ready: %w", err)
+	if result == controllerutil.OperationResultCreated {
+		recorder.Normal(EventReasonKustomizationCreated, eventActionBootstrap, "Created Flux Kustomization %s", client.ObjectKeyFromObject(kustomization))
 	}
 
-	log.Info("Successfully bootstrapped Flux Kustomization")
-
 	return nil
 }
 
+// CheckKustomization checks whether the Kustomization object specified in the given config is ready. See CheckObject.
+func CheckKustomization(ctx context.Context, c client.Reader, config *fluxv1alpha1.Kustomization) (done bool, err error) {
+	kustomization := config.Template.DeepCopy()
+	return CheckObject(ctx, c, kustomization, CheckFluxObject(kustomization))
+}
+
 // ConditionFunc checks the health of a polled object. If done==true, waiting should stop and propagate the returned
-// error. If done==false, the error is preserved but the check is retried.
+// error. If done==false, the error describes why the object is not ready yet and the check is retried later.
 type ConditionFunc func() (done bool, err error)
 
-// WaitForObject periodically reads the given object and waits for the given ConditionFunc to return done==true.
-// If the check times out, it returns the last error from the ConditionFunc.
-func WaitForObject(ctx context.Context, c client.Reader, obj client.Object, interval, timeout time.Duration, check ConditionFunc) error {
-	var lastError error
-	if err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
-		lastError = c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
-		if apierrors.IsNotFound(lastError) {
+// CheckObject reads the given object and runs the given ConditionFunc once. If the object doesn't exist yet, done is
+// false. If reading the object fails, done is true as this is considered a severe error.
+func CheckObject(ctx context.Context, c client.Reader, obj client.Object, check ConditionFunc) (done bool, err error) {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) {
 			// wait for the object to appear
-			return false, nil
-		}
-		if lastError != nil {
-			// severe error, fail immediately
-			return false, lastError
-		}
-
-		var done bool
-		done, lastError = check()
-		if done {
-			return true, lastError
-		}
-		return false, nil
-	}); err != nil {
-		// if we timed out waiting, return the last error that we observed instead of "context deadline exceeded" or similar
-		if lastError != nil {
-			return lastError
+			return false, fmt.Errorf("object %q does not exist yet", client.ObjectKeyFromObject(obj))
 		}
-		return err
+		return true, err
 	}
 
-	return nil
+	return check()
 }
 
 // CheckFluxObject returns a ConditionFunc that determines the health of Flux objects based on the Ready condition.
+// Failures carry Gardener error codes derived from the condition's reason and message.
 func CheckFluxObject(obj fluxmeta.ObjectWithConditions) ConditionFunc {
 	return func() (healthy bool, err error) {
 		if cond := meta.FindStatusCondition(obj.GetConditions(), fluxmeta.ReadyCondition); cond != nil {
@@ -514,10 +940,14 @@ func CheckFluxObject(obj fluxmeta.ObjectWithConditions) ConditionFunc {
 			case metav1.ConditionTrue:
 				return true, nil
 			case metav1.ConditionFalse:
-				return true, fmt.Errorf("reconciliation failed: %s", cond.Message)
+				return true, fluxConditionError("reconciliation failed", cond)
 			}
 		}
 
+		if cond := meta.FindStatusCondition(obj.GetConditions(), fluxmeta.StalledCondition); cond != nil && cond.Status == metav1.ConditionTrue {
+			return true, fluxConditionError("reconciliation stalled", cond)
+		}
+
 		return false, fmt.Errorf("has not been reconciled yet")
 	}
 }
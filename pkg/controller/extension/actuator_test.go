@@ -6,14 +6,18 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/fluxcd/flux2/v2/pkg/manifestgen"
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	fluxmeta "github.com/fluxcd/pkg/apis/meta"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/extensions"
+	. "github.com/gardener/gardener/pkg/utils/test/matchers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
@@ -30,6 +34,8 @@ import (
 
 	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1/validation"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
 var _ = Describe("DecodeProviderConfig", func() {
@@ -44,7 +50,9 @@ var _ = Describe("DecodeProviderConfig", func() {
 		Expect(fluxv1alpha1.AddToScheme(scheme)).To(Succeed())
 		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
 
-		a = NewActuator(fakeClient, "garden-id").(*actuator)
+		versions, err := fluxversions.DefaultCatalog(nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		a = NewActuator(fakeClient, nil, "garden-id", DefaultBootstrapOptions(), nil, nil, nil, versions).(*actuator)
 	})
 
 	Context("valid providerConfig given", func() {
@@ -60,18 +68,33 @@ flux:
 			Expect(config.Flux.Version).To(PointTo(Equal("v2.0.0")))
 			Expect(config.Flux.Namespace).To(PointTo(Equal("flux-system")))
 
-			Expect(validation.ValidateFluxConfig(config, nil, nil)).
+			Expect(validation.ValidateFluxConfig(config, nil, a.versions, nil)).
 				To(BeEmpty(), "decoded providerConfig should be accepted by validation")
 		})
 	})
 
+	Context("version constraint given", func() {
+		It("should resolve the constraint to the newest supported version", func() {
+			config, err := a.DecodeProviderConfig(&runtime.RawExtension{Raw: []byte(`apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+flux:
+  version: ~2.2
+`)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validation.ValidateFluxConfig(config, nil, a.versions, nil)).To(BeEmpty())
+
+			Expect(ResolveVersion(a.versions, config.Flux)).To(Succeed())
+			Expect(config.Flux.Version).To(PointTo(Equal("v2.2.3")))
+		})
+	})
+
 	Context("no providerConfig given", func() {
 		It("should default the providerConfig", func() {
 			config, err := a.DecodeProviderConfig(nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(config.Flux.Namespace).To(PointTo(Equal("flux-system")))
 
-			Expect(validation.ValidateFluxConfig(config, nil, nil)).
+			Expect(validation.ValidateFluxConfig(config, nil, a.versions, nil)).
 				To(BeEmpty(), "defaulted providerConfig should be accepted by validation")
 		})
 	})
@@ -79,12 +102,12 @@ flux:
 
 var _ = Describe("InstallFlux", func() {
 	var (
-		tmpDir      string
+		manifests   *fluxmanifests.Source
 		shootClient client.Client
 		config      *fluxv1alpha1.FluxInstallation
 	)
 	BeforeEach(func() {
-		tmpDir = setupManifests()
+		manifests = setupManifestsSource("v2.1.3")
 		shootClient = newShootClient()
 		config = &fluxv1alpha1.FluxInstallation{
 			Version:   ptr.To("v2.1.3"),
@@ -92,24 +115,114 @@ var _ = Describe("InstallFlux", func() {
 			Namespace: ptr.To("gotk-system"),
 		}
 	})
-	It("succesfully apply and wait for readiness", func() {
-		done := testAsync(func() {
-			Expect(
-				installFlux(ctx, log, shootClient, config, tmpDir, poll, timeout),
-			).To(Succeed())
-		})
-		Eventually(fakeFluxReady(ctx, shootClient, *config.Namespace)).Should(Succeed())
+	It("should successfully apply the install manifest", func() {
+		Expect(InstallFlux(ctx, log, shootClient, config, manifests, nil, nil)).To(Succeed())
 
-		Eventually(done).Should(BeClosed())
+		sourceController := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "source-controller", Namespace: "gotk-system"}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(sourceController), sourceController)).To(Succeed())
 	})
-	It("should fail if the resources do not get ready", func() {
-		done := testAsync(func() {
-			Expect(
-				installFlux(ctx, log, shootClient, config, tmpDir, poll, timeout),
-			).To(MatchError(ContainSubstring("error waiting for Flux installation to get ready")))
-		})
 
-		Eventually(done).Should(BeClosed())
+	It("should apply the image overrides", func() {
+		config.Images = []fluxv1alpha1.ComponentImage{{Component: "source-controller", Repository: ptr.To("mirror.example.com/source-controller")}}
+		Expect(InstallFlux(ctx, log, shootClient, config, manifests, nil, nil)).To(Succeed())
+
+		sourceController := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "source-controller", Namespace: "gotk-system"}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(sourceController), sourceController)).To(Succeed())
+		Expect(sourceController.Spec.Template.Spec.Containers[0].Image).To(Equal("mirror.example.com/source-controller:v1.9.3"))
+	})
+
+	It("should fail if an image digest cannot be pinned", func() {
+		config.PinImageDigests = ptr.To(true)
+		Expect(InstallFlux(ctx, log, shootClient, config, manifests, nil, nil)).To(MatchError(ContainSubstring("digest catalog does not contain a digest")))
+	})
+
+	It("should fail if the version is not available locally", func() {
+		config.Version = ptr.To("v2.0.0")
+		err := InstallFlux(ctx, log, shootClient, config, manifests, nil, nil)
+		Expect(err).To(MatchError(fluxmanifests.ErrVersionNotAvailable))
+		Expect(err).To(MatchError(ContainSubstring(`version "v2.0.0" is not available, available versions: [v2.1.3]`)))
+	})
+})
+
+var _ = Describe("validateInstallation", func() {
+	var (
+		a      *actuator
+		config *fluxv1alpha1.FluxInstallation
+	)
+
+	BeforeEach(func() {
+		versions, err := fluxversions.NewCatalog([]string{"v2.1.3"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		a = NewActuator(fake.NewClientBuilder().Build(), nil, "garden-id", DefaultBootstrapOptions(), setupManifestsSource("v2.1.3"), nil, fluxmanifests.DigestCatalog{}, versions).(*actuator)
+		config = &fluxv1alpha1.FluxInstallation{
+			Version:         ptr.To("~2.1"),
+			Registry:        ptr.To("reg.example.com"),
+			Namespace:       ptr.To("flux-system"),
+			PinImageDigests: ptr.To(true),
+		}
+	})
+
+	It("should resolve the version", func() {
+		config.PinImageDigests = nil
+		Expect(a.validateInstallation(config)).To(Succeed())
+		Expect(config.Version).To(PointTo(Equal("v2.1.3")))
+	})
+
+	It("should reject pinImageDigests if the catalog doesn't contain the digests of the version", func() {
+		err := a.validateInstallation(config)
+		Expect(err).To(MatchError(ContainSubstring("invalid providerConfig: flux.pinImageDigests: Invalid value: true: digests of Flux v2.1.3 are not available")))
+		Expect(err).To(MatchError(ContainSubstring("digest catalog does not contain a digest")))
+		Expect(err).To(BeAssignableToTypeOf(&gardencorev1beta1helper.ErrorWithCodes{}))
+	})
+
+	It("should accept pinImageDigests if the catalog contains all digests", func() {
+		manifest, err := BuildInstallManifest(&fluxv1alpha1.FluxInstallation{Version: ptr.To("v2.1.3"), Registry: ptr.To("reg.example.com"), Namespace: ptr.To("flux-system")}, a.manifests, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		for _, match := range regexp.MustCompile(`image: [^\s]*/([a-z-]+):([^\s@]+)`).FindAllStringSubmatch(string(manifest), -1) {
+			a.imageDigests[match[1]+":"+match[2]] = "sha256:" + strings.Repeat("a", 64)
+		}
+		Expect(a.imageDigests).NotTo(BeEmpty())
+
+		Expect(a.validateInstallation(config)).To(Succeed())
+	})
+
+	It("should accept pinImageDigests for the default Flux version with the shipped digest catalog", func() {
+		// the test manifests are generated for the Flux version in go.mod, which is the default Flux version
+		digests, err := fluxmanifests.DefaultDigestCatalog()
+		Expect(err).NotTo(HaveOccurred())
+		versions, err := fluxversions.DefaultCatalog(nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		a = NewActuator(fake.NewClientBuilder().Build(), nil, "garden-id", DefaultBootstrapOptions(), setupManifestsSource(fluxv1alpha1.DefaultFluxVersion), nil, digests, versions).(*actuator)
+
+		config.Version = ptr.To(fluxv1alpha1.DefaultFluxVersion)
+		Expect(a.validateInstallation(config)).To(Succeed())
+	})
+})
+
+var _ = Describe("CheckFluxInstallation", func() {
+	var (
+		shootClient client.Client
+		config      *fluxv1alpha1.FluxInstallation
+	)
+	BeforeEach(func() {
+		shootClient = newShootClient()
+		config = &fluxv1alpha1.FluxInstallation{
+			Version:   ptr.To("v2.1.3"),
+			Registry:  ptr.To("reg.example.com"),
+			Namespace: ptr.To("gotk-system"),
+		}
+	})
+	It("should fail if Flux has not been installed", func() {
+		Expect(CheckFluxInstallation(ctx, shootClient, config)).To(MatchError(ContainSubstring("does not exist yet")))
+	})
+	It("should fail if the resources are not ready", func() {
+		Expect(InstallFlux(ctx, log, shootClient, config, setupManifestsSource("v2.1.3"), nil, nil)).To(Succeed())
+		Expect(CheckFluxInstallation(ctx, shootClient, config)).NotTo(Succeed())
+	})
+	It("should succeed if the resources are ready", func() {
+		Expect(InstallFlux(ctx, log, shootClient, config, setupManifestsSource("v2.1.3"), nil, nil)).To(Succeed())
+		Expect(fakeFluxReady(ctx, shootClient, *config.Namespace)()).To(Succeed())
+		Expect(CheckFluxInstallation(ctx, shootClient, config)).To(Succeed())
 	})
 })
 
@@ -145,15 +258,16 @@ var _ = Describe("BootstrapSource", func() {
 			}
 		})
 
-		It("should successfully apply and wait for readiness", func() {
-			done := testAsync(func() {
-				Expect(
-					bootstrapSource(ctx, log, shootClient, config, poll, timeout),
-				).To(Succeed())
-			})
+		It("should successfully apply and check readiness", func() {
+			Expect(BootstrapSource(ctx, log, nil, shootClient, config, false)).To(Succeed())
+
+			done, err := CheckSource(ctx, shootClient, config)
+			Expect(done).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("has not been reconciled yet")))
+
 			repo := gitRepo.DeepCopy()
-			Eventually(fakeFluxResourceReady(ctx, shootClient, repo)).Should(Succeed())
-			Eventually(done).Should(BeClosed())
+			Expect(fakeFluxResourceReady(ctx, shootClient, repo)()).To(Succeed())
+			Expect(CheckSource(ctx, shootClient, config)).To(BeTrue())
 
 			createdRepo := &sourcev1.GitRepository{}
 			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(repo), createdRepo)).To(Succeed())
@@ -163,12 +277,14 @@ var _ = Describe("BootstrapSource", func() {
 			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ns), ns)).Should(Succeed())
 		})
 
-		It("should fail if the resources do not get ready", func() {
-			Eventually(testAsync(func() {
-				Expect(
-					bootstrapSource(ctx, log, shootClient, config, poll, timeout),
-				).To(MatchError(ContainSubstring("error waiting for GitRepository to get ready")))
-			})).Should(BeClosed())
+		It("should fail if the resources failed to get ready", func() {
+			Expect(BootstrapSource(ctx, log, nil, shootClient, config, false)).To(Succeed())
+			repo := gitRepo.DeepCopy()
+			Expect(fakeFluxResourceFailed(ctx, shootClient, repo, "authentication required")()).To(Succeed())
+
+			done, err := CheckSource(ctx, shootClient, config)
+			Expect(done).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("reconciliation failed: authentication required")))
 		})
 	})
 
@@ -198,15 +314,14 @@ var _ = Describe("BootstrapSource", func() {
 			}
 		})
 
-		It("should successfully apply and wait for readiness", func() {
-			done := testAsync(func() {
-				Expect(
-					bootstrapSource(ctx, log, shootClient, config, poll, timeout),
-				).To(Succeed())
-			})
+		It("should successfully apply and check readiness", func() {
+			Expect(BootstrapSource(ctx, log, nil, shootClient, config, false)).To(Succeed())
+			done, _ := CheckSource(ctx, shootClient, config)
+			Expect(done).To(BeFalse())
+
 			repo := ociRepo.DeepCopy()
-			Eventually(fakeFluxResourceReady(ctx, shootClient, repo)).Should(Succeed())
-			Eventually(done).Should(BeClosed())
+			Expect(fakeFluxResourceReady(ctx, shootClient, repo)()).To(Succeed())
+			Expect(CheckSource(ctx, shootClient, config)).To(BeTrue())
 
 			createdRepo := &sourcev1.OCIRepository{}
 			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(repo), createdRepo)).To(Succeed())
@@ -217,31 +332,16 @@ var _ = Describe("BootstrapSource", func() {
 			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ns), ns)).Should(Succeed())
 		})
 
-		It("should fail if the resources do not get ready", func() {
-			Eventually(testAsync(func() {
-				Expect(
-					bootstrapSource(ctx, log, shootClient, config, poll, timeout),
-				).To(MatchError(ContainSubstring("error waiting for OCIRepository to get ready")))
-			})).Should(BeClosed())
-		})
-
 		It("should handle OCI with semver reference", func() {
 			ociRepo.Spec.Reference = &sourcev1.OCIRepositoryRef{
 				SemVer: ">= 1.0.0",
 			}
 			config.Template = encodeSourceObject(ociRepo)
 
-			done := testAsync(func() {
-				Expect(
-					bootstrapSource(ctx, log, shootClient, config, poll, timeout),
-				).To(Succeed())
-			})
-			repo := ociRepo.DeepCopy()
-			Eventually(fakeFluxResourceReady(ctx, shootClient, repo)).Should(Succeed())
-			Eventually(done).Should(BeClosed())
+			Expect(BootstrapSource(ctx, log, nil, shootClient, config, false)).To(Succeed())
 
 			createdRepo := &sourcev1.OCIRepository{}
-			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(repo), createdRepo)).To(Succeed())
+			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ociRepo), createdRepo)).To(Succeed())
 			Expect(createdRepo.Spec.Reference.SemVer).To(Equal(">= 1.0.0"))
 		})
 	})
@@ -251,7 +351,7 @@ var _ = Describe("BootstrapSource", func() {
 			config = &fluxv1alpha1.Source{}
 
 			Expect(
-				bootstrapSource(ctx, log, shootClient, config, poll, timeout),
+				BootstrapSource(ctx, log, nil, shootClient, config, false),
 			).To(MatchError(ContainSubstring("source template is required")))
 		})
 
@@ -261,7 +361,7 @@ var _ = Describe("BootstrapSource", func() {
 			}
 
 			Expect(
-				bootstrapSource(ctx, log, shootClient, config, poll, timeout),
+				BootstrapSource(ctx, log, nil, shootClient, config, false),
 			).To(MatchError(ContainSubstring("failed to decode source template")))
 		})
 	})
@@ -286,16 +386,17 @@ var _ = Describe("BootstrapKustomization", func() {
 			},
 		}
 	})
-	It("should succesfully apply and wait for readiness", func() {
-		done := testAsync(func() {
-			Expect(bootstrapKustomization(ctx, log, shootClient, config, poll, timeout)).To(Succeed())
-		})
+	It("should succesfully apply and check readiness", func() {
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config, false)).To(Succeed())
+		done, _ := CheckKustomization(ctx, shootClient, config)
+		Expect(done).To(BeFalse())
+
 		ks := config.Template.DeepCopy()
-		Eventually(fakeFluxResourceReady(ctx, shootClient, ks)).Should(Succeed())
-		Eventually(done).Should(BeClosed())
+		Expect(fakeFluxResourceReady(ctx, shootClient, ks)()).To(Succeed())
+		Expect(CheckKustomization(ctx, shootClient, config)).To(BeTrue())
 
 		createdKS := &kustomizev1.Kustomization{}
-		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ks), createdKS))
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ks), createdKS)).To(Succeed())
 		Expect(createdKS.Spec.Path).To(Equal("/some/path"))
 
 		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: config.Template.Namespace}}
@@ -305,19 +406,15 @@ var _ = Describe("BootstrapKustomization", func() {
 		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: config.Template.Namespace}}
 		Expect(shootClient.Create(ctx, ns)).To(Succeed())
 
-		done := testAsync(func() {
-			Expect(bootstrapKustomization(ctx, log, shootClient, config, poll, timeout)).To(Succeed())
-		})
-		ks := config.Template.DeepCopy()
-		Eventually(fakeFluxResourceReady(ctx, shootClient, ks)).Should(Succeed())
-		Eventually(done).Should(BeClosed())
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config, false)).To(Succeed())
 	})
-	It("should fail if the resources do not get ready", func() {
-		Eventually(testAsync(func() {
-			Expect(
-				bootstrapKustomization(ctx, log, shootClient, config, poll, timeout),
-			).To(MatchError(ContainSubstring("error waiting for Kustomization to get ready")))
-		})).Should(BeClosed())
+	It("should fail if the resources failed to get ready", func() {
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config, false)).To(Succeed())
+		Expect(fakeFluxResourceFailed(ctx, shootClient, config.Template.DeepCopy(), "kustomization path not found")()).To(Succeed())
+
+		done, err := CheckKustomization(ctx, shootClient, config)
+		Expect(done).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("kustomization path not found")))
 	})
 })
 
@@ -344,6 +441,55 @@ var _ = Describe("Bootstrapped Condition", func() {
 	})
 })
 
+var _ = Describe("GenerateInstallManifestFromSource", func() {
+	It("should generate the manifest from the local manifests", func() {
+		out, err := GenerateInstallManifestFromSource(&fluxv1alpha1.FluxInstallation{
+			Version:   ptr.To("v2.1.3"),
+			Registry:  ptr.To("registry.example.com"),
+			Namespace: ptr.To("a-namespace"),
+		}, setupManifestsSource("v2.1.3"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(And(
+			ContainSubstring("v2.1.3"),
+			ContainSubstring("registry.example.com"),
+			ContainSubstring("a-namespace"),
+		))
+	})
+})
+
+var _ = Describe("GenerateInstallManifestCached", func() {
+	It("should cache the manifest by the install options and the manifest source", func() {
+		cache := fluxmanifests.NewCache(DefaultManifestCacheSize, "")
+		dir := GinkgoT().TempDir()
+		Expect(os.CopyFS(filepath.Join(dir, "v2.1.3"), os.DirFS("./testdata/fluxmanifests"))).To(Succeed())
+		manifests := fluxmanifests.NewSource(dir)
+		config := &fluxv1alpha1.FluxInstallation{
+			Version:   ptr.To("v2.1.3"),
+			Registry:  ptr.To("registry.example.com"),
+			Namespace: ptr.To("a-namespace"),
+		}
+
+		out, err := GenerateInstallManifestCached(config, manifests, cache)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("generating the manifest for other options")
+		otherConfig := config.DeepCopy()
+		otherConfig.Registry = ptr.To("other.example.com")
+		otherOut, err := GenerateInstallManifestCached(otherConfig, manifests, cache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(otherOut)).To(ContainSubstring("other.example.com"))
+
+		By("not generating the manifests again")
+		Expect(os.RemoveAll(filepath.Join(dir, "v2.1.3"))).To(Succeed())
+		Expect(GenerateInstallManifestCached(config, manifests, cache)).To(Equal(out))
+		Expect(GenerateInstallManifestCached(otherConfig, fluxmanifests.NewSource(dir), cache)).To(Equal(otherOut))
+
+		By("generating the manifest for another source")
+		_, err = GenerateInstallManifestCached(config, fluxmanifests.NewSource(GinkgoT().TempDir()), cache)
+		Expect(err).To(MatchError(fluxmanifests.ErrVersionNotAvailable))
+	})
+})
+
 var _ = Describe("GenerateInstallManifest", func() {
 	It("should contain the provided options", func() {
 		dir := setupManifests()
@@ -395,6 +541,7 @@ var _ = Describe("ReconcileShootInfoConfigMap", func() {
 		config          *fluxv1alpha1.FluxConfig
 		cluster         *extensions.Cluster
 		configMap       *corev1.ConfigMap
+		expectedData    map[string]string
 	)
 
 	BeforeEach(func() {
@@ -412,6 +559,29 @@ var _ = Describe("ReconcileShootInfoConfigMap", func() {
 				ObjectMeta: metav1.ObjectMeta{
 					Name: shootName,
 				},
+				Spec: gardencorev1beta1.ShootSpec{
+					Region:  "eu01",
+					Purpose: ptr.To(gardencorev1beta1.ShootPurposeProduction),
+					DNS: &gardencorev1beta1.DNS{
+						Domain: ptr.To("test-shoot.asdf-test.example.com"),
+					},
+					Kubernetes: gardencorev1beta1.Kubernetes{
+						Version: "1.35.1",
+					},
+					Networking: &gardencorev1beta1.Networking{
+						Pods:     ptr.To("100.64.0.0/12"),
+						Services: ptr.To("100.80.0.0/12"),
+						Nodes:    ptr.To("10.250.0.0/16"),
+					},
+					Provider: gardencorev1beta1.Provider{
+						Type: "stackit",
+						Workers: []gardencorev1beta1.Worker{
+							{Name: "pool-a", Zones: []string{"eu01-2", "eu01-1"}},
+							{Name: "pool-b", Zones: []string{"eu01-1"}},
+						},
+					},
+					SeedName: ptr.To("seed-eu01"),
+				},
 				Status: gardencorev1beta1.ShootStatus{
 					TechnicalID:     technicalID,
 					ClusterIdentity: &clusterIdentity,
@@ -424,18 +594,31 @@ var _ = Describe("ReconcileShootInfoConfigMap", func() {
 				Namespace: *config.Flux.Namespace,
 			},
 		}
+		expectedData = map[string]string{
+			"SHOOT_INFO_CLUSTER_IDENTITY":        clusterIdentity,
+			"SHOOT_INFO_NAME":                    shootName,
+			"SHOOT_INFO_TECHNICAL_ID":            technicalID,
+			"SHOOT_INFO_GARDEN_CLUSTER_IDENTITY": "garden-id",
+			"SHOOT_INFO_PROJECT_NAME":            "asdf-test",
+			"SHOOT_INFO_REGION":                  "eu01",
+			"SHOOT_INFO_PROVIDER_TYPE":           "stackit",
+			"SHOOT_INFO_KUBERNETES_VERSION":      "1.35.1",
+			"SHOOT_INFO_SEED_NAME":               "seed-eu01",
+			"SHOOT_INFO_PURPOSE":                 "production",
+			"SHOOT_INFO_DOMAIN":                  "test-shoot.asdf-test.example.com",
+			"SHOOT_INFO_POD_CIDR":                "100.64.0.0/12",
+			"SHOOT_INFO_SERVICE_CIDR":            "100.80.0.0/12",
+			"SHOOT_INFO_NODE_CIDR":               "10.250.0.0/16",
+			"SHOOT_INFO_WORKER_POOLS":            "pool-a,pool-b",
+			"SHOOT_INFO_WORKER_ZONES":            "eu01-1,eu01-2",
+		}
 	})
 
 	It("should apply successfully and contain expected keys", func() {
 		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
 
 		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
-		Expect(configMap.Data).To(Equal(map[string]string{
-			"SHOOT_INFO_CLUSTER_IDENTITY":        clusterIdentity,
-			"SHOOT_INFO_NAME":                    shootName,
-			"SHOOT_INFO_TECHNICAL_ID":            technicalID,
-			"SHOOT_INFO_GARDEN_CLUSTER_IDENTITY": "garden-id",
-		}))
+		Expect(configMap.Data).To(Equal(expectedData))
 	})
 
 	It("should overwrite changes in existing configmap", func() {
@@ -451,14 +634,213 @@ var _ = Describe("ReconcileShootInfoConfigMap", func() {
 
 		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
 		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
-		Expect(configMap.Data).To(Equal(map[string]string{
-			"SHOOT_INFO_CLUSTER_IDENTITY":        clusterIdentity,
-			"SHOOT_INFO_NAME":                    shootName,
-			"SHOOT_INFO_TECHNICAL_ID":            technicalID,
-			"SHOOT_INFO_GARDEN_CLUSTER_IDENTITY": "garden-id",
-		}))
+		Expect(configMap.Data).To(Equal(expectedData))
 		Expect(configMap.Data).ToNot(HaveKey("FOOBAR"))
 	})
+
+	It("should add user-defined data and projected Shoot metadata", func() {
+		cluster.Shoot.Labels = map[string]string{
+			"example.com/cost-center": "1234",
+			"example.com/environment": "prod",
+			"other.io/ignored":        "true",
+		}
+		cluster.Shoot.Annotations = map[string]string{
+			"team.example.com/owner": "team-a",
+		}
+		config.ShootInfo = &fluxv1alpha1.ShootInfo{
+			Data: map[string]string{
+				"CUSTOM_KEY":        "custom",
+				"SHOOT_INFO_REGION": "overwritten",
+			},
+			LabelPrefixes:      []string{"example.com/"},
+			AnnotationPrefixes: []string{"team.example.com/"},
+		}
+
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		expectedData["CUSTOM_KEY"] = "custom"
+		expectedData["SHOOT_INFO_LABEL_EXAMPLE_COM_COST_CENTER"] = "1234"
+		expectedData["SHOOT_INFO_LABEL_EXAMPLE_COM_ENVIRONMENT"] = "prod"
+		expectedData["SHOOT_INFO_ANNOTATION_TEAM_EXAMPLE_COM_OWNER"] = "team-a"
+		Expect(configMap.Data).To(Equal(expectedData))
+	})
+
+	It("should publish the enabled extensions and selected providerConfig fields", func() {
+		cluster.Shoot.Spec.Extensions = []gardencorev1beta1.Extension{
+			{Type: "shoot-flux"},
+			{
+				Type: "shoot-cert-service",
+				ProviderConfig: &runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"service.cert.extensions.gardener.cloud/v1alpha1","kind":"CertConfig","shootIssuers":{"enabled":true},"issuers":[{"name":"garden"}]}`),
+				},
+			},
+			{Type: "registry-cache", Disabled: ptr.To(true)},
+		}
+		config.ShootInfo = &fluxv1alpha1.ShootInfo{
+			Extensions: []fluxv1alpha1.ShootInfoExtension{
+				{Type: "shoot-cert-service", Fields: []string{"shootIssuers.enabled", "issuers", "missing.field"}},
+				{Type: "registry-cache", Fields: []string{"caches"}},
+			},
+		}
+
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		expectedData["SHOOT_INFO_EXTENSIONS"] = "shoot-cert-service,shoot-flux"
+		expectedData["SHOOT_INFO_EXTENSION_SHOOT_FLUX_ENABLED"] = "true"
+		expectedData["SHOOT_INFO_EXTENSION_SHOOT_CERT_SERVICE_ENABLED"] = "true"
+		expectedData["SHOOT_INFO_EXTENSION_SHOOT_CERT_SERVICE_SHOOTISSUERS_ENABLED"] = "true"
+		expectedData["SHOOT_INFO_EXTENSION_SHOOT_CERT_SERVICE_ISSUERS"] = `[{"name":"garden"}]`
+		Expect(configMap.Data).To(Equal(expectedData))
+	})
+
+	It("should replicate the ConfigMap into additional namespaces and clean up", func() {
+		config.ShootInfo = &fluxv1alpha1.ShootInfo{
+			Namespaces: []string{"tenant-a", "tenant-b"},
+		}
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
+
+		for _, namespace := range []string{"flux-system", "tenant-a", "tenant-b"} {
+			replica := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: shootInfoConfigMapName, Namespace: namespace}}
+			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(replica), replica)).To(Succeed())
+			Expect(replica.Data).To(Equal(expectedData))
+		}
+
+		By("removing a namespace from the list")
+		config.ShootInfo.Namespaces = []string{"tenant-a"}
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
+
+		replica := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: shootInfoConfigMapName, Namespace: "tenant-a"}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(replica), replica)).To(Succeed())
+		replica = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: shootInfoConfigMapName, Namespace: "tenant-b"}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(replica), replica)).To(BeNotFoundError())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+	})
+
+	It("should omit keys for unset optional fields", func() {
+		cluster.Shoot.Spec.SeedName = nil
+		cluster.Shoot.Spec.Purpose = nil
+		cluster.Shoot.Spec.DNS = nil
+		cluster.Shoot.Spec.Networking = nil
+		cluster.Shoot.Spec.Provider.Workers = nil
+		cluster.Shoot.Status.TechnicalID = "shoot-legacy-id"
+
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, "garden-id")).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue("SHOOT_INFO_REGION", "eu01"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_PROJECT_NAME"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_SEED_NAME"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_PURPOSE"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_DOMAIN"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_POD_CIDR"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_WORKER_POOLS"))
+		Expect(configMap.Data).NotTo(HaveKey("SHOOT_INFO_WORKER_ZONES"))
+	})
+})
+
+var _ = Describe("ReconcileShootInfoSecret", func() {
+	var (
+		shootClient client.Client
+		config      *fluxv1alpha1.FluxConfig
+		cluster     *extensions.Cluster
+		secret      *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		shootClient = newShootClient()
+		config = &fluxv1alpha1.FluxConfig{
+			Flux: &fluxv1alpha1.FluxInstallation{
+				Namespace: ptr.To("flux-system"),
+			},
+			ShootInfo: &fluxv1alpha1.ShootInfo{
+				Secret: &fluxv1alpha1.ShootInfoSecret{
+					Keys: []string{
+						fluxv1alpha1.ShootInfoSecretKeyAPIServerURL,
+						fluxv1alpha1.ShootInfoSecretKeyCABundle,
+						fluxv1alpha1.ShootInfoSecretKeyIngressDomain,
+					},
+				},
+			},
+		}
+		cluster = &extensions.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				Spec: gardencorev1beta1.ShootSpec{
+					DNS: &gardencorev1beta1.DNS{
+						Domain: ptr.To("test-shoot.asdf-test.example.com"),
+					},
+				},
+				Status: gardencorev1beta1.ShootStatus{
+					AdvertisedAddresses: []gardencorev1beta1.ShootAdvertisedAddress{
+						{Name: "internal", URL: "https://api.internal.example.com"},
+						{Name: "external", URL: "https://api.test-shoot.asdf-test.example.com"},
+					},
+				},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      shootInfoSecretName,
+				Namespace: *config.Flux.Namespace,
+			},
+		}
+
+		Expect(shootClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kube-root-ca.crt",
+				Namespace: *config.Flux.Namespace,
+			},
+			Data: map[string]string{
+				"ca.crt": "ca-bundle",
+			},
+		})).To(Succeed())
+	})
+
+	It("should create the Secret with the selected keys", func() {
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue(managedByLabelKey, managedByLabelValue))
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			"SHOOT_INFO_API_SERVER_URL": []byte("https://api.test-shoot.asdf-test.example.com"),
+			"SHOOT_INFO_CA_BUNDLE":      []byte("ca-bundle"),
+			"SHOOT_INFO_INGRESS_DOMAIN": []byte("*.ingress.test-shoot.asdf-test.example.com"),
+		}))
+	})
+
+	It("should only add the selected keys", func() {
+		config.ShootInfo.Secret.Keys = []string{fluxv1alpha1.ShootInfoSecretKeyAPIServerURL}
+		cluster.Shoot.Status.AdvertisedAddresses = nil
+
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			"SHOOT_INFO_API_SERVER_URL": []byte("https://api.test-shoot.asdf-test.example.com"),
+		}))
+	})
+
+	It("should delete the Secret if it is no longer configured", func() {
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+
+		config.ShootInfo = nil
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(BeNotFoundError())
+
+		By("ignoring a missing Secret")
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+	})
+
+	It("should not delete a Secret that is not managed by the extension", func() {
+		secret.StringData = map[string]string{"foo": "bar"}
+		Expect(shootClient.Create(ctx, secret)).To(Succeed())
+
+		config.ShootInfo = nil
+		Expect(ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster)).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+	})
 })
 
 func fakeFluxResourceReady(ctx context.Context, c client.Client, obj fluxmeta.ObjectWithConditionsSetter) func() error {
@@ -475,6 +857,21 @@ func fakeFluxResourceReady(ctx context.Context, c client.Client, obj fluxmeta.Ob
 	}
 }
 
+func fakeFluxResourceFailed(ctx context.Context, c client.Client, obj fluxmeta.ObjectWithConditionsSetter, message string) func() error {
+	return func() error {
+		cObj := obj.(client.Object)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cObj), cObj); err != nil {
+			return err
+		}
+		obj.SetConditions([]metav1.Condition{{
+			Type:    fluxmeta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Message: message,
+		}})
+		return c.Status().Update(ctx, cObj)
+	}
+}
+
 // encodeSourceObject encodes a Flux source object (GitRepository or OCIRepository) into a runtime.RawExtension
 func encodeSourceObject(obj runtime.Object) *runtime.RawExtension {
 	scheme := runtime.NewScheme()
@@ -560,6 +957,17 @@ func setupManifests() string {
 	return tmpDir
 }
 
+// setupManifestsSource returns a fluxmanifests.Source providing the test manifests for the given version.
+func setupManifestsSource(version string) *fluxmanifests.Source {
+	tmpDir, err := manifestgen.MkdirTempAbs("", "gardener-extension-shoot-flux")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(func() {
+		os.RemoveAll(tmpDir)
+	})
+	Expect(os.CopyFS(filepath.Join(tmpDir, version), os.DirFS("./testdata/fluxmanifests"))).To(Succeed())
+	return fluxmanifests.NewSource(tmpDir)
+}
+
 func copyFile(src, dst string) error {
 	source, err := os.Open(src)
 	if err != nil {
@@ -7,18 +7,28 @@ import (
 	"github.com/gardener/gardener/extensions/pkg/controller/extension"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
 )
 
 const (
 	// ControllerName is the name of the controller.
 	ControllerName = "extension"
+	// DefaultManifestCacheSize is the default maximum size of the generated Flux install manifests kept in memory.
+	DefaultManifestCacheSize = 64 << 20
 )
 
 var (
 	// DefaultAddOptions are the default AddOptions for AddToManager.
-	DefaultAddOptions = AddOptions{}
+	DefaultAddOptions = AddOptions{
+		Bootstrap:     DefaultBootstrapOptions(),
+		FluxManifests: fluxmanifests.NewSource(""),
+		ManifestCache: fluxmanifests.NewCache(DefaultManifestCacheSize, ""),
+	}
 )
 
 // AddOptions are options to apply when adding the extension controller to the manager.
@@ -29,18 +39,40 @@ type AddOptions struct {
 	IgnoreOperationAnnotation bool
 
 	GardenClusterIdentity string
+	// Bootstrap configures the polling and timeouts of the initial bootstrap of Flux.
+	Bootstrap BootstrapOptions
+	// FluxManifests provides the Flux install manifests for air-gapped installations. If no local manifests are
+	// available, the manifests are downloaded from GitHub.
+	FluxManifests *fluxmanifests.Source
+	// ManifestCache caches the generated Flux install manifests across all reconciliations.
+	ManifestCache *fluxmanifests.Cache
+	// ImageDigests is the digest catalog used for pinning the images of the Flux components to digests.
+	ImageDigests fluxmanifests.DigestCatalog
+	// FluxVersions is the catalog of supported Flux versions. The configured versions and constraints are validated
+	// and resolved against it. If nil, the configured versions are used as is.
+	FluxVersions *fluxversions.Catalog
 }
 
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
+	// reconcile immediately when a rebootstrap is requested, even without the gardener operation annotation
+	predicates := []predicate.Predicate{predicate.Or(
+		predicate.And(extension.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation)...),
+		HasRebootstrapAnnotation(),
+	)}
+
+	if err := metrics.Registry.Register(newBootstrappedShootsCollector(mgr.GetClient(), mgr.GetScheme())); err != nil {
+		return err
+	}
+
 	return extension.Add(mgr, extension.AddArgs{
-		Actuator:          NewActuator(mgr.GetClient(), opts.GardenClusterIdentity),
+		Actuator:          NewActuator(mgr.GetClient(), mgr.GetEventRecorder("gardener-extension-"+fluxv1alpha1.ExtensionType), opts.GardenClusterIdentity, opts.Bootstrap, opts.FluxManifests, opts.ManifestCache, opts.ImageDigests, opts.FluxVersions),
 		ControllerOptions: opts.Controller,
 		Name:              ControllerName,
 		FinalizerSuffix:   fluxv1alpha1.ExtensionType,
 		Resync:            60 * time.Minute,
-		Predicates:        extension.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
+		Predicates:        predicates,
 		Type:              fluxv1alpha1.ExtensionType,
 	})
 }
@@ -0,0 +1,320 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/extensions"
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// bootstrap performs the initial bootstrap of Flux as a sequence of phases: install, source, kustomization. The current
+// phase is recorded in the Extension's providerStatus. Instead of blocking the reconcile worker while waiting for the
+// objects of a phase to get ready, bootstrap returns a RequeueAfterError, so that the readiness is checked again in the
+// next reconciliation. If a phase doesn't complete within its timeout, the bootstrap is started from scratch in the next
+// reconciliation. The poll interval and timeouts are determined by the BootstrapOptions and FluxConfig.bootstrap.
+func (a *actuator) bootstrap(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	shootClient client.Client,
+	ext *extensionsv1alpha1.Extension,
+	config *fluxv1alpha1.FluxConfig,
+	cluster *extensions.Cluster,
+) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+
+	settings := a.bootstrapOptions.settingsFor(config)
+	suspended := ptr.Deref(config.Suspend, false)
+
+	var phase fluxv1alpha1.BootstrapPhase
+	if status.Bootstrap != nil {
+		phase = status.Bootstrap.Phase
+	}
+
+	if phase == "" {
+		recorder.Normal(EventReasonInstallingFlux, eventActionInstall, "Installing Flux %s", ptr.Deref(config.Flux.Version, ""))
+		if err := InstallFlux(ctx, log, shootClient, config.Flux, a.manifests, a.manifestCache, a.imageDigests); err != nil {
+			return fmt.Errorf("error installing Flux: %w", err)
+		}
+		status.Version = ptr.Deref(config.Flux.Version, "")
+		status.Suspended = suspended
+		phase = fluxv1alpha1.BootstrapPhaseInstall
+		if err := a.setBootstrapPhase(ctx, ext, status, phase); err != nil {
+			return err
+		}
+	}
+
+	// the objects created in the following phases are suspended according to the current configuration, the objects
+	// created in previous phases are suspended or resumed if the configuration changed in the meantime
+	if err := a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config); err != nil {
+		return fmt.Errorf("error reconciling suspension of Flux objects: %w", err)
+	}
+
+	if phase == fluxv1alpha1.BootstrapPhaseInstall {
+		if err := CheckFluxInstallation(ctx, shootClient, config.Flux); err != nil {
+			return a.waitForBootstrapPhase(ctx, log, recorder, ext, status, settings.pollInterval, settings.installTimeout, false, fmt.Errorf("error waiting for Flux installation to get ready: %w", err))
+		}
+		log.Info("Successfully installed Flux")
+		recorder.Normal(EventReasonFluxInstalled, eventActionInstall, "Installed Flux %s", ptr.Deref(config.Flux.Version, ""))
+
+		phase = fluxv1alpha1.BootstrapPhaseSource
+		if err := a.setBootstrapPhase(ctx, ext, status, phase); err != nil {
+			return err
+		}
+	}
+
+	// secrets might be necessary for the source to get ready
+	if err := ReconcileSecrets(ctx, log, recorder, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+		return fmt.Errorf("error reconciling secrets: %w", err)
+	}
+
+	// configMaps might be necessary for the kustomization to get ready
+	if err := ReconcileConfigMaps(ctx, log, recorder, a.client, shootClient, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+		return fmt.Errorf("error reconciling ConfigMaps: %w", err)
+	}
+
+	if phase == fluxv1alpha1.BootstrapPhaseSource {
+		if config.Source != nil {
+			if err := BootstrapSource(ctx, log, recorder, shootClient, config.Source, suspended); err != nil {
+				return fmt.Errorf("error bootstrappping Flux source: %w", err)
+			}
+			// suspended objects are not reconciled by Flux, so they never get ready
+			if ptr.Deref(config.Source.WaitForReady, true) && !suspended {
+				if done, err := CheckSource(ctx, shootClient, config.Source); !done || err != nil {
+					return a.waitForBootstrapPhase(ctx, log, recorder, ext, status, settings.pollInterval, settings.sourceTimeout, done, fmt.Errorf("error waiting for Flux source to get ready: %w", err))
+				}
+				recorder.Normal(EventReasonSourceReady, eventActionBootstrap, "Flux source is ready at revision %q", sourceRevision(ctx, shootClient, config.Source))
+			}
+			log.Info("Successfully bootstrapped Flux source")
+		}
+
+		phase = fluxv1alpha1.BootstrapPhaseKustomization
+		if err := a.setBootstrapPhase(ctx, ext, status, phase); err != nil {
+			return err
+		}
+	}
+
+	// configMap might be necessary for the kustomization to get ready
+	if err := ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, a.gardenClusterIdentity); err != nil {
+		return fmt.Errorf("error reconciling ConfigMap %q: %w", shootInfoConfigMapName, err)
+	}
+
+	if err := ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster); err != nil {
+		return fmt.Errorf("error reconciling Secret %q: %w", shootInfoSecretName, err)
+	}
+
+	if phase == fluxv1alpha1.BootstrapPhaseKustomization {
+		if config.Kustomization != nil {
+			if err := BootstrapKustomization(ctx, log, recorder, shootClient, config.Kustomization, suspended); err != nil {
+				return fmt.Errorf("error bootstrappping Flux Kustomization: %w", err)
+			}
+			if ptr.Deref(config.Kustomization.WaitForReady, true) && !suspended {
+				if done, err := CheckKustomization(ctx, shootClient, config.Kustomization); !done || err != nil {
+					return a.waitForBootstrapPhase(ctx, log, recorder, ext, status, settings.pollInterval, settings.kustomizationTimeout, done, fmt.Errorf("error waiting for Flux Kustomization to get ready: %w", err))
+				}
+				recorder.Normal(EventReasonKustomizationReady, eventActionBootstrap, "Flux Kustomization is ready at revision %q", kustomizationRevision(ctx, shootClient, config.Kustomization))
+			}
+			log.Info("Successfully bootstrapped Flux Kustomization")
+		}
+
+		phase = fluxv1alpha1.BootstrapPhaseDone
+		if err := a.setBootstrapPhase(ctx, ext, status, phase); err != nil {
+			return err
+		}
+		recorder.Normal(EventReasonBootstrapCompleted, eventActionBootstrap, "Flux has been bootstrapped successfully")
+		recordBootstrapResult(bootstrapResultSucceeded, phase, nil)
+	}
+
+	if err := SetFluxBootstrapped(ctx, a.client, ext); err != nil {
+		return fmt.Errorf("error marking successful boostrapping: %w", err)
+	}
+
+	return a.reportReadiness(ctx, recorder, shootClient, ext, config)
+}
+
+// reportReadiness records the readiness of the source and Kustomization in the Extension's providerStatus if the
+// extension doesn't wait for them to get ready (waitForReady=false). Readiness problems are only reported as status and
+// events and never returned as an error. The result of a previous dry run is removed from the status and the
+// suspension of the objects is reported.
+func (a *actuator) reportReadiness(ctx context.Context, recorder *EventRecorder, shootClient client.Client, ext *extensionsv1alpha1.Extension, config *fluxv1alpha1.FluxConfig) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+	oldStatus := status.DeepCopy()
+
+	status.DryRun = nil
+	status.Suspended = ptr.Deref(config.Suspend, false)
+	status.Source = nil
+	if config.Source != nil && !ptr.Deref(config.Source.WaitForReady, true) {
+		status.Source = objectStatus(CheckSource(ctx, shootClient, config.Source))
+	}
+	status.Kustomization = nil
+	if config.Kustomization != nil && !ptr.Deref(config.Kustomization.WaitForReady, true) {
+		status.Kustomization = objectStatus(CheckKustomization(ctx, shootClient, config.Kustomization))
+	}
+
+	if apiequality.Semantic.DeepEqual(oldStatus, status) {
+		return nil
+	}
+	if status.Source != nil && !apiequality.Semantic.DeepEqual(oldStatus.Source, status.Source) {
+		recordReadiness(recorder, status.Source, EventReasonSourceReady, EventReasonSourceNotReady, "Flux source")
+	}
+	if status.Kustomization != nil && !apiequality.Semantic.DeepEqual(oldStatus.Kustomization, status.Kustomization) {
+		recordReadiness(recorder, status.Kustomization, EventReasonKustomizationReady, EventReasonKustomizationNotReady, "Flux Kustomization")
+	}
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error reporting readiness in Extension status: %w", err)
+	}
+	return nil
+}
+
+// recordReadiness records an event for the given readiness of an object that is not waited for.
+func recordReadiness(recorder *EventRecorder, status *fluxv1alpha1.ObjectStatus, readyReason, notReadyReason, description string) {
+	if status.Ready {
+		recorder.Normal(readyReason, eventActionBootstrap, "%s is ready", description)
+		return
+	}
+	recorder.Warning(notReadyReason, eventActionBootstrap, "%s is not ready: %s", description, status.Message)
+}
+
+// sourceRevision returns the revision of the artifact of the source object specified in the given config. It returns
+// an empty string if the revision cannot be determined.
+func sourceRevision(ctx context.Context, c client.Reader, config *fluxv1alpha1.Source) string {
+	source, err := decodeSourceObject(config)
+	if err != nil {
+		return ""
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(source), source); err != nil {
+		return ""
+	}
+	if withArtifact, ok := source.(interface{ GetArtifact() *fluxmeta.Artifact }); ok && withArtifact.GetArtifact() != nil {
+		return withArtifact.GetArtifact().Revision
+	}
+	return ""
+}
+
+// kustomizationRevision returns the last applied revision of the Kustomization specified in the given config. It
+// returns an empty string if the revision cannot be determined.
+func kustomizationRevision(ctx context.Context, c client.Reader, config *fluxv1alpha1.Kustomization) string {
+	kustomization := config.Template.DeepCopy()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(kustomization), kustomization); err != nil {
+		return ""
+	}
+	return kustomization.Status.LastAppliedRevision
+}
+
+// objectStatus converts the result of a readiness check to an ObjectStatus.
+func objectStatus(done bool, err error) *fluxv1alpha1.ObjectStatus {
+	if done && err == nil {
+		return &fluxv1alpha1.ObjectStatus{Ready: true}
+	}
+	return &fluxv1alpha1.ObjectStatus{Message: err.Error()}
+}
+
+// waitForBootstrapPhase returns a RequeueAfterError after the given poll interval if the current bootstrap phase is still within the given timeout
+// and the check is not done yet. Otherwise, the bootstrap progress is reset and the given error is returned.
+func (a *actuator) waitForBootstrapPhase(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	ext *extensionsv1alpha1.Extension,
+	status *fluxv1alpha1.FluxStatus,
+	pollInterval time.Duration,
+	timeout time.Duration,
+	done bool,
+	err error,
+) error {
+	// Error codes are only determined for permanent failures and timeouts, as gardenlet doesn't retry errors with
+	// non-retryable error codes.
+	if done {
+		// the check failed permanently, the phase is retried in the next reconciliation
+		recorder.Warning(EventReasonBootstrapFailed, eventActionBootstrap, "Bootstrap phase %s failed: %v", status.Bootstrap.Phase, err)
+		err = determineError(err)
+		recordBootstrapResult(bootstrapResultFailed, status.Bootstrap.Phase, err)
+		return err
+	}
+
+	if time.Since(status.Bootstrap.LastTransitionTime.Time) < timeout {
+		log.Info("Waiting for bootstrap phase to complete", "phase", status.Bootstrap.Phase, "reason", err.Error())
+		// only record a single Event per phase, the individual polls are logged
+		if !status.Bootstrap.WaitingEventRecorded {
+			recorder.Normal(EventReasonWaitingForBootstrapPhase, eventActionBootstrap, "Waiting for bootstrap phase %s: %v", status.Bootstrap.Phase, err)
+			status.Bootstrap.WaitingEventRecorded = true
+			if updateErr := a.updateProviderStatus(ctx, ext, status); updateErr != nil {
+				return fmt.Errorf("error recording waiting Event in Extension status: %w", updateErr)
+			}
+		}
+		return &reconcilerutils.RequeueAfterError{RequeueAfter: pollInterval, Cause: err}
+	}
+
+	phase := status.Bootstrap.Phase
+	recorder.Warning(EventReasonBootstrapTimedOut, eventActionBootstrap, "Bootstrap phase %s timed out after %s, restarting the bootstrap: %v", phase, timeout, err)
+	if resetErr := a.setBootstrapPhase(ctx, ext, status, ""); resetErr != nil {
+		return resetErr
+	}
+	err = determineError(fmt.Errorf("timed out waiting for bootstrap phase %s after %s: %w", phase, timeout, err))
+	recordBootstrapResult(bootstrapResultTimedOut, phase, err)
+	return err
+}
+
+// setBootstrapPhase records the given bootstrap phase in the Extension's providerStatus. If phase is empty, the
+// bootstrap progress is removed from the status.
+func (a *actuator) setBootstrapPhase(ctx context.Context, ext *extensionsv1alpha1.Extension, status *fluxv1alpha1.FluxStatus, phase fluxv1alpha1.BootstrapPhase) error {
+	if status.Bootstrap != nil && phase != "" {
+		// the previous phase has completed successfully
+		observeBootstrapPhase(status.Bootstrap)
+	}
+
+	if phase == "" {
+		status.Bootstrap = nil
+	} else {
+		status.Bootstrap = &fluxv1alpha1.BootstrapStatus{
+			Phase:              phase,
+			LastTransitionTime: metav1.Now(),
+		}
+	}
+
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error recording bootstrap phase %q in Extension status: %w", phase, err)
+	}
+	return nil
+}
+
+// updateProviderStatus patches the providerStatus of the given Extension.
+func (a *actuator) updateProviderStatus(ctx context.Context, ext *extensionsv1alpha1.Extension, status *fluxv1alpha1.FluxStatus) error {
+	raw, err := encodeProviderStatus(status)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(ext.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	ext.Status.ProviderStatus = raw
+	return a.client.Status().Patch(ctx, ext, patch)
+}
+
+// encodeProviderStatus encodes the given status for the providerStatus of an Extension.
+func encodeProviderStatus(status *fluxv1alpha1.FluxStatus) (*runtime.RawExtension, error) {
+	status.SetGroupVersionKind(fluxv1alpha1.SchemeGroupVersion.WithKind("FluxStatus"))
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
@@ -0,0 +1,304 @@
+package extension
+
+import (
+	"strings"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/extensions"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("bootstrap", func() {
+	var (
+		seedClient   client.Client
+		shootClient  client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+		a            *actuator
+
+		ext           *extensionsv1alpha1.Extension
+		config        *fluxv1alpha1.FluxConfig
+		cluster       *extensions.Cluster
+		gitRepo       *sourcev1.GitRepository
+		kustomization *kustomizev1.Kustomization
+	)
+
+	BeforeEach(func() {
+		seedClient = newSeedClient()
+		shootClient = newShootClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		a = NewActuator(seedClient, fakeRecorder, "garden-id", DefaultBootstrapOptions(), setupManifestsSource("v2.1.3"), nil, nil, nil).(*actuator)
+
+		ext = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shoot-flux",
+				Namespace: "shoot--foo--bar",
+			},
+		}
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		recorder = NewEventRecorder(fakeRecorder, ext)
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: sourcev1.GitRepositorySpec{
+				URL: "http://example.com",
+			},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Path: "/some/path",
+			},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Flux: &fluxv1alpha1.FluxInstallation{
+				Version:   ptr.To("v2.1.3"),
+				Registry:  ptr.To("reg.example.com"),
+				Namespace: ptr.To("flux-system"),
+			},
+			Source: &fluxv1alpha1.Source{
+				Template: encodeSourceObject(gitRepo.DeepCopy()),
+			},
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: *kustomization.DeepCopy(),
+			},
+		}
+		cluster = &extensions.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar"},
+				Status: gardencorev1beta1.ShootStatus{
+					TechnicalID:     "shoot--foo--bar",
+					ClusterIdentity: ptr.To("cluster-identity"),
+				},
+			},
+		}
+	})
+
+	bootstrapPhase := func() fluxv1alpha1.BootstrapPhase {
+		GinkgoHelper()
+		Expect(seedClient.Get(ctx, client.ObjectKeyFromObject(ext), ext)).To(Succeed())
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		if status.Bootstrap == nil {
+			return ""
+		}
+		return status.Bootstrap.Phase
+	}
+
+	recordedEvents := func() []string {
+		var events []string
+		for {
+			select {
+			case event := <-fakeRecorder.Events:
+				events = append(events, event)
+			default:
+				return events
+			}
+		}
+	}
+
+	It("should progress through the bootstrap phases without blocking", func() {
+		By("installing Flux")
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseInstall))
+
+		By("waiting for the installation")
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseInstall))
+		Expect(fakeFluxReady(ctx, shootClient, "flux-system")()).To(Succeed())
+
+		By("bootstrapping the source")
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseSource))
+		Expect(fakeFluxResourceReady(ctx, shootClient, gitRepo)()).To(Succeed())
+
+		By("bootstrapping the Kustomization")
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseKustomization))
+		Expect(shootClient.Get(ctx, client.ObjectKey{Namespace: "flux-system", Name: shootInfoConfigMapName}, &corev1.ConfigMap{})).To(Succeed())
+		Expect(fakeFluxResourceReady(ctx, shootClient, kustomization)()).To(Succeed())
+
+		By("marking the bootstrap as done")
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+
+		Expect(recordedEvents()).To(ConsistOf(
+			"Normal InstallingFlux Installing Flux v2.1.3",
+			ContainSubstring("Normal WaitingForBootstrapPhase Waiting for bootstrap phase Install"),
+			"Normal FluxInstalled Installed Flux v2.1.3",
+			"Normal SourceCreated Created Flux GitRepository flux-system/flux-system",
+			"Normal WaitingForBootstrapPhase Waiting for bootstrap phase Source: error waiting for Flux source to get ready: has not been reconciled yet",
+			"Normal SourceReady Flux source is ready at revision \"\"",
+			"Normal KustomizationCreated Created Flux Kustomization flux-system/flux-system",
+			"Normal WaitingForBootstrapPhase Waiting for bootstrap phase Kustomization: error waiting for Flux Kustomization to get ready: has not been reconciled yet",
+			"Normal KustomizationReady Flux Kustomization is ready at revision \"\"",
+			"Normal BootstrapCompleted Flux has been bootstrapped successfully",
+		))
+	})
+
+	It("should only install Flux if no source and Kustomization are configured", func() {
+		config.Source = nil
+		config.Kustomization = nil
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(fakeFluxReady(ctx, shootClient, "flux-system")()).To(Succeed())
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+	})
+
+	It("should not wait for objects with waitForReady=false and report their readiness", func() {
+		config.Source.WaitForReady = ptr.To(false)
+		config.Kustomization.WaitForReady = ptr.To(false)
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(fakeFluxReady(ctx, shootClient, "flux-system")()).To(Succeed())
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), &sourcev1.GitRepository{})).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(kustomization), &kustomizev1.Kustomization{})).To(Succeed())
+
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Source).To(Equal(&fluxv1alpha1.ObjectStatus{Message: "has not been reconciled yet"}))
+		Expect(status.Kustomization).To(Equal(&fluxv1alpha1.ObjectStatus{Message: "has not been reconciled yet"}))
+
+		By("reporting readiness changes")
+		Expect(fakeFluxResourceReady(ctx, shootClient, gitRepo)()).To(Succeed())
+		Expect(fakeFluxResourceFailed(ctx, shootClient, kustomization, "kustomization path not found")()).To(Succeed())
+		Expect(a.reportReadiness(ctx, recorder, shootClient, ext, config)).To(Succeed())
+
+		status, err = a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Bootstrap.Phase).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(status.Source).To(Equal(&fluxv1alpha1.ObjectStatus{Ready: true}))
+		Expect(status.Kustomization).To(Equal(&fluxv1alpha1.ObjectStatus{Message: "reconciliation failed: kustomization path not found"}))
+		Expect(recordedEvents()).To(ContainElements(
+			"Normal SourceReady Flux source is ready",
+			"Warning KustomizationNotReady Flux Kustomization is not ready: reconciliation failed: kustomization path not found",
+		))
+	})
+
+	It("should bootstrap suspended objects without waiting for them and report the suspension", func() {
+		config.Suspend = ptr.To(true)
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(fakeFluxReady(ctx, shootClient, "flux-system")()).To(Succeed())
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), gitRepo)).To(Succeed())
+		Expect(gitRepo.Spec.Suspend).To(BeTrue())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(kustomization), kustomization)).To(Succeed())
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Suspended).To(BeTrue())
+		// the objects are created suspended
+		Expect(recordedEvents()).NotTo(ContainElement(ContainSubstring("Suspended Flux")))
+	})
+
+	It("should fail if the source failed to get ready", func() {
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(fakeFluxReady(ctx, shootClient, "flux-system")()).To(Succeed())
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(fakeFluxResourceFailed(ctx, shootClient, gitRepo, "authentication required")()).To(Succeed())
+
+		err := a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)
+		Expect(err).NotTo(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(err).To(MatchError(ContainSubstring("authentication required")))
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+		Expect(recordedEvents()).To(ContainElement("Warning BootstrapFailed Bootstrap phase Source failed: error waiting for Flux source to get ready: reconciliation failed: authentication required"))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseSource))
+	})
+
+	It("should restart the bootstrap if a phase times out", func() {
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+
+		Expect(a.updateProviderStatus(ctx, ext, &fluxv1alpha1.FluxStatus{
+			Bootstrap: &fluxv1alpha1.BootstrapStatus{
+				Phase:              fluxv1alpha1.BootstrapPhaseInstall,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-DefaultBootstrapOptions().InstallTimeout)),
+			},
+		})).To(Succeed())
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(MatchError(ContainSubstring("timed out waiting for bootstrap phase Install")))
+		Expect(bootstrapPhase()).To(BeEmpty())
+	})
+
+	It("should record the waiting Event only once per phase", func() {
+		waitingEvents := func() []string {
+			var events []string
+			for _, event := range recordedEvents() {
+				if strings.Contains(event, EventReasonWaitingForBootstrapPhase) {
+					events = append(events, event)
+				}
+			}
+			return events
+		}
+
+		for range 3 {
+			Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		}
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseInstall))
+		Expect(waitingEvents()).To(HaveLen(1))
+
+		By("recording the Event again after the phase timed out")
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		status.Bootstrap.LastTransitionTime = metav1.NewTime(time.Now().Add(-DefaultBootstrapOptions().InstallTimeout))
+		Expect(a.updateProviderStatus(ctx, ext, status)).To(Succeed())
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(MatchError(ContainSubstring("timed out waiting for bootstrap phase Install")))
+
+		for range 2 {
+			Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		}
+		Expect(waitingEvents()).To(HaveLen(1))
+	})
+
+	It("should use the timeout and poll interval configured for the shoot", func() {
+		config.Bootstrap = &fluxv1alpha1.Bootstrap{
+			PollInterval:   &metav1.Duration{Duration: 20 * time.Second},
+			InstallTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+		}
+
+		err := a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)
+		Expect(err).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(err.(*reconcilerutils.RequeueAfterError).RequeueAfter).To(Equal(20 * time.Second))
+
+		Expect(a.updateProviderStatus(ctx, ext, &fluxv1alpha1.FluxStatus{
+			Bootstrap: &fluxv1alpha1.BootstrapStatus{
+				Phase:              fluxv1alpha1.BootstrapPhaseInstall,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+			},
+		})).To(Succeed())
+
+		Expect(a.bootstrap(ctx, log, recorder, shootClient, ext, config, cluster)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(bootstrapPhase()).To(Equal(fluxv1alpha1.BootstrapPhaseInstall))
+	})
+})
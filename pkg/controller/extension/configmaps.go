@@ -0,0 +1,132 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// ReconcileConfigMaps copies all ConfigMaps referenced in additionalConfigMapResources, and deletes all ConfigMaps
+// that are no longer referenced in any namespace. The shoot-info ConfigMap is managed by the extension as well, but is
+// never pruned.
+func ReconcileConfigMaps(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	seedClient client.Client,
+	shootClient client.Client,
+	seedNamespace string,
+	config *fluxv1alpha1.FluxConfig,
+	resources []gardencorev1beta1.NamedResourceReference,
+) error {
+	shootNamespace := *config.Flux.Namespace
+	configMapsToKeep := sets.Set[client.ObjectKey]{}
+
+	for _, resource := range config.AdditionalConfigMapResources {
+		key, err := copyConfigMapToShoot(ctx, log, recorder, seedClient, shootClient, seedNamespace, shootNamespace, resources, resource)
+		if err != nil {
+			return fmt.Errorf("failed to copy ConfigMap: %w", err)
+		}
+		configMapsToKeep.Insert(key)
+	}
+
+	// cleanup unreferenced ConfigMaps, the shoot-info ConfigMaps are cleaned up by ReconcileShootInfoConfigMap
+	shootInfoConfigMapKeys := sets.New[client.ObjectKey]()
+	for namespace := range shootInfoNamespaces(config) {
+		shootInfoConfigMapKeys.Insert(client.ObjectKey{Namespace: namespace, Name: shootInfoConfigMapName})
+	}
+	configMapList := &corev1.ConfigMapList{}
+	if err := shootClient.List(ctx, configMapList,
+		client.MatchingLabels{managedByLabelKey: managedByLabelValue},
+	); err != nil {
+		return fmt.Errorf("failed to list managed ConfigMaps in shoot: %w", err)
+	}
+	for _, configMap := range configMapList.Items {
+		if key := client.ObjectKeyFromObject(&configMap); shootInfoConfigMapKeys.Has(key) || configMapsToKeep.Has(key) {
+			continue
+		}
+		if err := shootClient.Delete(ctx, &configMap); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ConfigMap that is no longer referenced: %w", err)
+		}
+		log.Info("Deleted ConfigMap that is no longer referenced by the extension", "configMap", client.ObjectKeyFromObject(&configMap))
+		recorder.Normal(EventReasonConfigMapDeleted, eventActionDelete, "Deleted ConfigMap %s that is no longer referenced", client.ObjectKeyFromObject(&configMap))
+	}
+	return nil
+}
+
+func copyConfigMapToShoot(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	seedClient client.Client,
+	shootClient client.Client,
+	seedNamespace string,
+	defaultNamespace string,
+	resources []gardencorev1beta1.NamedResourceReference,
+	additionalResource fluxv1alpha1.AdditionalResource,
+) (client.ObjectKey, error) {
+	resource := v1beta1helper.GetResourceByName(resources, additionalResource.Name)
+	if resource == nil {
+		return client.ObjectKey{}, fmt.Errorf("configmap resource name does not match any of the resource names in Shoot.spec.resources[].name")
+	}
+
+	seedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      v1beta1constants.ReferencedResourcesPrefix + resource.ResourceRef.Name,
+			Namespace: seedNamespace,
+		},
+	}
+	if err := seedClient.Get(ctx, client.ObjectKeyFromObject(seedConfigMap), seedConfigMap); err != nil {
+		return client.ObjectKey{}, fmt.Errorf("error reading referenced ConfigMap: %w", err)
+	}
+	presentKeys := sets.KeySet(seedConfigMap.Data).Union(sets.KeySet(seedConfigMap.BinaryData))
+	if missing := missingDataKeys(additionalResource.Keys, presentKeys); len(missing) > 0 {
+		return client.ObjectKey{}, fmt.Errorf("referenced ConfigMap %q does not contain keys %v", resource.ResourceRef.Name, missing)
+	}
+
+	name := resource.ResourceRef.Name
+	if additionalResource.TargetName != nil {
+		name = *additionalResource.TargetName
+	}
+	shootConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: additionalResourceTargetNamespace(additionalResource, defaultNamespace),
+		},
+	}
+
+	if shootConfigMap.Namespace != defaultNamespace {
+		if err := ensureNamespace(ctx, shootClient, shootConfigMap.Namespace); err != nil {
+			return client.ObjectKey{}, err
+		}
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, shootConfigMap, func() error {
+		shootConfigMap.Data = mapDataKeys(seedConfigMap.Data, additionalResource.Keys)
+		shootConfigMap.BinaryData = mapDataKeys(seedConfigMap.BinaryData, additionalResource.Keys)
+		shootConfigMap.Labels = additionalResourceLabels(additionalResource, seedConfigMap.Labels, ptr.Deref(additionalResource.CopyLabels, false))
+		shootConfigMap.Annotations = additionalResourceAnnotations(additionalResource, shootConfigMap.Annotations)
+		return nil
+	})
+	if err != nil {
+		return client.ObjectKey{}, err
+	}
+	log.Info("Synced ConfigMap", "configMap", client.ObjectKeyFromObject(shootConfigMap), "result", result)
+	if result != controllerutil.OperationResultNone {
+		recorder.Normal(EventReasonConfigMapSynced, eventActionSync, "Synced ConfigMap %s (%s)", client.ObjectKeyFromObject(shootConfigMap), result)
+	}
+
+	return client.ObjectKeyFromObject(shootConfigMap), nil
+}
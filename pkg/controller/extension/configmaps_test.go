@@ -0,0 +1,172 @@
+package extension
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/gardener/gardener/pkg/utils/test/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("ReconcileConfigMaps", Ordered, func() {
+	var (
+		shootClient client.Client
+		seedClient  client.Client
+
+		config    *fluxv1alpha1.FluxConfig
+		resources []gardencorev1beta1.NamedResourceReference
+		extNS     = "ext-ns"
+	)
+	BeforeAll(func() {
+		shootClient = newShootClient()
+		seedClient = newSeedClient()
+		config = &fluxv1alpha1.FluxConfig{
+			Flux: &fluxv1alpha1.FluxInstallation{
+				Namespace: ptr.To("flux-system"),
+			},
+			AdditionalConfigMapResources: []fluxv1alpha1.AdditionalResource{{
+				Name: "extra-configmap",
+			}},
+		}
+		resources = []gardencorev1beta1.NamedResourceReference{
+			{
+				Name: "extra-configmap",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{
+					Name: "extra",
+					Kind: "ConfigMap",
+				},
+			},
+		}
+		Expect(seedClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ref-extra",
+				Namespace: extNS,
+			},
+			Data: map[string]string{
+				"ENVIRONMENT": "staging",
+			},
+			BinaryData: map[string][]byte{
+				"blob": []byte("extra"),
+			},
+		})).To(Succeed())
+		Expect(shootClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      shootInfoConfigMapName,
+				Namespace: "flux-system",
+				Labels: map[string]string{
+					managedByLabelKey: managedByLabelValue,
+				},
+			},
+		})).To(Succeed())
+	})
+
+	It("should create the additional ConfigMaps", func() {
+		Expect(
+			ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		createdConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "extra",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdConfigMap), createdConfigMap)).To(Succeed())
+		Expect(createdConfigMap.Data).To(HaveKeyWithValue("ENVIRONMENT", "staging"))
+		Expect(createdConfigMap.BinaryData).To(HaveKeyWithValue("blob", []byte("extra")))
+		Expect(createdConfigMap.Labels).To(HaveKeyWithValue(managedByLabelKey, managedByLabelValue))
+	})
+
+	It("should change an existing ConfigMap", func() {
+		createdConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "extra",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdConfigMap), createdConfigMap)).To(Succeed())
+		createdConfigMap.Data["ENVIRONMENT"] = "changed"
+		Expect(shootClient.Update(ctx, createdConfigMap)).To(Succeed())
+
+		Expect(
+			ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdConfigMap), createdConfigMap)).To(Succeed())
+		Expect(createdConfigMap.Data).To(HaveKeyWithValue("ENVIRONMENT", "staging"))
+	})
+
+	It("should respect the target name and clean up the old ConfigMap", func() {
+		config.AdditionalConfigMapResources[0].TargetName = ptr.To("surprise")
+		Expect(
+			ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		createdConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "surprise",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdConfigMap), createdConfigMap)).To(Succeed())
+		Expect(createdConfigMap.Data).To(HaveKeyWithValue("ENVIRONMENT", "staging"))
+
+		deletedConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "extra",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(deletedConfigMap), deletedConfigMap)).To(BeNotFoundError())
+	})
+
+	It("should not delete the shoot-info ConfigMap", func() {
+		config.AdditionalConfigMapResources = nil
+		Expect(
+			ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		shootInfo := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      shootInfoConfigMapName,
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(shootInfo), shootInfo)).To(Succeed())
+
+		deletedConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "surprise",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(deletedConfigMap), deletedConfigMap)).To(BeNotFoundError())
+	})
+
+	It("should only keep managed shoot-info ConfigMaps in the shoot-info namespaces", func() {
+		config.ShootInfo = &fluxv1alpha1.ShootInfo{Namespaces: []string{"apps"}}
+		for _, namespace := range []string{"apps", "other"} {
+			Expect(shootClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      shootInfoConfigMapName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						managedByLabelKey: managedByLabelValue,
+					},
+				},
+			})).To(Succeed())
+		}
+
+		Expect(
+			ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		for _, namespace := range []string{"flux-system", "apps"} {
+			shootInfo := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      shootInfoConfigMapName,
+				Namespace: namespace,
+			}}
+			Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(shootInfo), shootInfo)).To(Succeed())
+		}
+
+		deletedConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      shootInfoConfigMapName,
+			Namespace: "other",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(deletedConfigMap), deletedConfigMap)).To(BeNotFoundError())
+	})
+})
@@ -3,7 +3,15 @@ package extension
 import fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 
 const (
-	managedByLabelKey      = "app.kubernetes.io/managed-by"
+	managedByLabelKey      = fluxv1alpha1.LabelManagedBy
 	managedByLabelValue    = "gardener-extension-" + fluxv1alpha1.ExtensionType
-	shootInfoConfigMapName = "shoot-info"
+	shootInfoConfigMapName = fluxv1alpha1.ShootInfoConfigMapName
+	shootInfoSecretName    = fluxv1alpha1.ShootInfoSecretName
+
+	// copyLabelsAnnotation on a referenced secret instructs the extension to copy its labels to the shoot. It is only
+	// respected for backwards-compatibility, AdditionalResource.CopyLabels should be used instead.
+	copyLabelsAnnotation = "gardener-extension-shoot-flux/copy-labels"
+	// managedAnnotationsAnnotation on a synced resource in the shoot lists the annotations applied from
+	// AdditionalResource.Annotations, so that only these annotations are removed once they are no longer declared.
+	managedAnnotationsAnnotation = "gardener-extension-shoot-flux/managed-annotations"
 )
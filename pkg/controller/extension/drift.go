@@ -0,0 +1,134 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// DetectDrift compares the live source and Kustomization in the shoot with the defaulted templates of the given config.
+// It returns a description of every object that differs from its template, e.g.,
+// "GitRepository flux-system/flux-system: spec.ref.branch". Only fields set in the templates are compared, so fields
+// defaulted by the API server or Flux are not reported. Objects that don't exist in the shoot are reported as well.
+// The live objects are read as unstructured objects, so shootClient doesn't need the Flux types in its scheme.
+func DetectDrift(ctx context.Context, shootClient client.Reader, config *fluxv1alpha1.FluxConfig) ([]string, error) {
+	objects, err := bootstrapObjects(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []string
+	for _, object := range objects {
+		objectDrift, err := detectObjectDrift(ctx, shootClient, object)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, objectDrift...)
+	}
+	return drift, nil
+}
+
+// bootstrapObject is an object bootstrapped from a template of the FluxConfig.
+type bootstrapObject struct {
+	gvk      schema.GroupVersionKind
+	template client.Object
+}
+
+// String returns a description of the object for logs, events, and conditions, e.g.,
+// "GitRepository flux-system/flux-system".
+func (o bootstrapObject) String() string {
+	return fmt.Sprintf("%s %s", o.gvk.Kind, client.ObjectKeyFromObject(o.template))
+}
+
+// bootstrapObjects returns the source and Kustomization bootstrapped from the templates of the given config.
+func bootstrapObjects(config *fluxv1alpha1.FluxConfig) ([]bootstrapObject, error) {
+	var objects []bootstrapObject
+
+	if config.Source != nil {
+		source, err := decodeSourceObject(config.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		var kind string
+		switch source.(type) {
+		case *sourcev1.GitRepository:
+			kind = sourcev1.GitRepositoryKind
+		case *sourcev1.OCIRepository:
+			kind = sourcev1.OCIRepositoryKind
+		default:
+			return nil, fmt.Errorf("unsupported source type: %T", source)
+		}
+		objects = append(objects, bootstrapObject{gvk: sourcev1.GroupVersion.WithKind(kind), template: source})
+	}
+
+	if config.Kustomization != nil {
+		objects = append(objects, bootstrapObject{
+			gvk:      kustomizev1.GroupVersion.WithKind(kustomizev1.KustomizationKind),
+			template: &config.Kustomization.Template,
+		})
+	}
+
+	return objects, nil
+}
+
+// detectObjectDrift compares the spec of the live object with the spec of its template.
+func detectObjectDrift(ctx context.Context, c client.Reader, object bootstrapObject) ([]string, error) {
+	description := object.String()
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(object.gvk)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(object.template), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return []string{description + " does not exist"}, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", description, err)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object.template)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	driftedFields(&fields, "spec", content["spec"], live.Object["spec"])
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	slices.Sort(fields)
+	return []string{description + ": " + strings.Join(fields, ", ")}, nil
+}
+
+// driftedFields adds the paths of all fields set in templateValue that differ in liveValue to fields. Lists are compared
+// as a whole.
+func driftedFields(fields *[]string, path string, templateValue, liveValue any) {
+	if templateValue == nil {
+		return
+	}
+
+	templateMap, ok := templateValue.(map[string]any)
+	if !ok {
+		if !apiequality.Semantic.DeepEqual(templateValue, liveValue) {
+			*fields = append(*fields, path)
+		}
+		return
+	}
+
+	liveMap, _ := liveValue.(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(templateMap)) {
+		driftedFields(fields, path+"."+key, templateMap[key], liveMap[key])
+	}
+}
@@ -0,0 +1,101 @@
+package extension
+
+import (
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("DetectDrift", func() {
+	var (
+		shootClient   client.Client
+		config        *fluxv1alpha1.FluxConfig
+		gitRepo       *sourcev1.GitRepository
+		kustomization *kustomizev1.Kustomization
+	)
+
+	BeforeEach(func() {
+		shootClient = newShootClient()
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: sourcev1.GitRepositorySpec{
+				URL:       "http://example.com",
+				Reference: &sourcev1.GitRepositoryRef{Branch: "main"},
+				Interval:  metav1.Duration{Duration: time.Minute},
+			},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Path: "/some/path",
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{
+					Kind: sourcev1.GitRepositoryKind,
+					Name: "flux-system",
+				},
+			},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Source: &fluxv1alpha1.Source{
+				Template: encodeSourceObject(gitRepo.DeepCopy()),
+			},
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: *kustomization.DeepCopy(),
+			},
+		}
+	})
+
+	It("should not report drift if the objects match the templates", func() {
+		Expect(BootstrapSource(ctx, log, nil, shootClient, config.Source, false)).To(Succeed())
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, false)).To(Succeed())
+
+		Expect(DetectDrift(ctx, shootClient, config)).To(BeEmpty())
+	})
+
+	It("should ignore fields that are not set in the templates", func() {
+		gitRepo.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+		Expect(shootClient.Create(ctx, gitRepo)).To(Succeed())
+		kustomization.Spec.Prune = true
+		kustomization.Spec.Suspend = false
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, false)).To(Succeed())
+
+		Expect(DetectDrift(ctx, shootClient, config)).To(BeEmpty())
+	})
+
+	It("should report the fields that differ from the templates", func() {
+		gitRepo.Spec.Reference.Branch = "personal"
+		gitRepo.Spec.URL = "http://example.com/fork"
+		Expect(shootClient.Create(ctx, gitRepo)).To(Succeed())
+		kustomization.Spec.Path = "/other/path"
+		Expect(shootClient.Create(ctx, kustomization)).To(Succeed())
+
+		Expect(DetectDrift(ctx, shootClient, config)).To(HaveExactElements(
+			"GitRepository flux-system/flux-system: spec.ref.branch, spec.url",
+			"Kustomization flux-system/flux-system: spec.path",
+		))
+	})
+
+	It("should report missing objects", func() {
+		Expect(DetectDrift(ctx, shootClient, config)).To(HaveExactElements(
+			"GitRepository flux-system/flux-system does not exist",
+			"Kustomization flux-system/flux-system does not exist",
+		))
+	})
+
+	It("should not compare anything if no source and Kustomization are configured", func() {
+		Expect(DetectDrift(ctx, shootClient, &fluxv1alpha1.FluxConfig{})).To(BeEmpty())
+	})
+})
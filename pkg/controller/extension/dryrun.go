@@ -0,0 +1,299 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/extensions"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// maxChangedFields is the maximum number of changed fields listed per object in the dry-run status.
+const maxChangedFields = 10
+
+// dryRun computes the changes a full bootstrap would make to the shoot without applying them: the Flux installation,
+// Secrets, ConfigMaps, source, shoot-info, Kustomization, and suspension. The changes are recorded in the Extension's
+// providerStatus and summarised in an event if they differ from the previous dry run. The bootstrap progress is left
+// untouched.
+func (a *actuator) dryRun(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	shootClient client.Client,
+	ext *extensionsv1alpha1.Extension,
+	config *fluxv1alpha1.FluxConfig,
+	cluster *extensions.Cluster,
+) error {
+	log = log.WithValues("dryRun", true)
+	log.Info("Computing changes to the shoot without applying them")
+
+	c := NewDryRunClient(shootClient)
+
+	// objects are not actually changed, so don't record any events for them
+	if err := InstallFlux(ctx, log, c, config.Flux, a.manifests, a.manifestCache, a.imageDigests); err != nil {
+		return fmt.Errorf("error installing Flux in dry-run mode: %w", err)
+	}
+	if err := ReconcileSecrets(ctx, log, nil, a.client, c, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+		return fmt.Errorf("error reconciling secrets in dry-run mode: %w", err)
+	}
+	if err := ReconcileConfigMaps(ctx, log, nil, a.client, c, ext.Namespace, config, cluster.Shoot.Spec.Resources); err != nil {
+		return fmt.Errorf("error reconciling ConfigMaps in dry-run mode: %w", err)
+	}
+	if config.Source != nil {
+		if err := BootstrapSource(ctx, log, nil, c, config.Source, ptr.Deref(config.Suspend, false)); err != nil {
+			return fmt.Errorf("error bootstrappping Flux source in dry-run mode: %w", err)
+		}
+	}
+	if err := ReconcileShootInfoConfigMap(ctx, log, c, config, cluster, a.gardenClusterIdentity); err != nil {
+		return fmt.Errorf("error reconciling ConfigMap %q in dry-run mode: %w", shootInfoConfigMapName, err)
+	}
+	if err := ReconcileShootInfoSecret(ctx, log, c, config, cluster); err != nil {
+		return fmt.Errorf("error reconciling Secret %q in dry-run mode: %w", shootInfoSecretName, err)
+	}
+	if config.Kustomization != nil {
+		if err := BootstrapKustomization(ctx, log, nil, c, config.Kustomization, ptr.Deref(config.Suspend, false)); err != nil {
+			return fmt.Errorf("error bootstrappping Flux Kustomization in dry-run mode: %w", err)
+		}
+	}
+
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+
+	changes := c.Changes()
+	if status.DryRun == nil || !apiequality.Semantic.DeepEqual(status.DryRun.Changes, changes) {
+		recorder.Normal(EventReasonDryRunCompleted, eventActionDryRun, "Dry run completed: %s", summarizeChanges(changes))
+	}
+	log.Info("Computed changes to the shoot", "changes", len(changes))
+
+	status.DryRun = &fluxv1alpha1.DryRunStatus{
+		LastRunTime: metav1.Now(),
+		Changes:     changes,
+	}
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error reporting dry-run result in Extension status: %w", err)
+	}
+	return nil
+}
+
+// summarizeChanges returns a short summary of the given changes for events.
+func summarizeChanges(changes []fluxv1alpha1.ObjectChange) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	counts := map[fluxv1alpha1.ChangeAction]int{}
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+	return fmt.Sprintf("%d objects would be created, %d updated, %d deleted, see the providerStatus for details",
+		counts[fluxv1alpha1.ChangeActionCreate], counts[fluxv1alpha1.ChangeActionUpdate], counts[fluxv1alpha1.ChangeActionDelete])
+}
+
+// DryRunClient wraps a client for the shoot and sends all write requests as dry-run requests, i.e., the API server
+// validates and defaults them without persisting any change. It records the changes the requests would make.
+// As nothing is persisted, objects that depend on other created objects cannot be validated by the API server:
+// creating an object of a kind without CustomResourceDefinition (e.g., before Flux is installed) or in a namespace that
+// would be created is recorded without validation.
+type DryRunClient struct {
+	client.Client
+
+	changes []fluxv1alpha1.ObjectChange
+	// created contains the objects whose creation was recorded. As they are not persisted, they would be created again
+	// by every caller, e.g., the Flux namespace.
+	created sets.Set[dryRunObject]
+}
+
+// dryRunObject identifies an object created by a DryRunClient.
+type dryRunObject struct {
+	gvk schema.GroupVersionKind
+	key client.ObjectKey
+}
+
+// NewDryRunClient returns a DryRunClient for the given client.
+func NewDryRunClient(c client.Client) *DryRunClient {
+	return &DryRunClient{
+		Client:  client.NewDryRunClient(c),
+		created: sets.New[dryRunObject](),
+	}
+}
+
+// Changes returns the changes recorded so far.
+func (c *DryRunClient) Changes() []fluxv1alpha1.ObjectChange {
+	return slices.Clone(c.changes)
+}
+
+// Get reads the given object. If the object's kind doesn't exist yet, the object is reported as not found.
+func (c *DryRunClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if meta.IsNoMatchError(err) {
+		return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	return err
+}
+
+// Create records the creation of the given object. Objects whose creation was recorded before are only recorded once.
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	object := dryRunObject{gvk: gvk, key: client.ObjectKeyFromObject(obj)}
+	if c.created.Has(object) {
+		return nil
+	}
+
+	if err := c.Client.Create(ctx, obj, opts...); err != nil && !c.dependsOnCreatedObject(obj, err) {
+		return err
+	}
+
+	if _, err := c.record(fluxv1alpha1.ChangeActionCreate, obj, nil); err != nil {
+		return err
+	}
+	c.created.Insert(object)
+	return nil
+}
+
+// Update records the changed fields of the given object.
+func (c *DryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.recordUpdate(ctx, obj, func() error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+// Patch records the changed fields of the given object.
+func (c *DryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.recordUpdate(ctx, obj, func() error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+// Delete records the deletion of the given object. Like a regular deletion, it fails if the object doesn't exist.
+func (c *DryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return err
+	}
+
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	_, err := c.record(fluxv1alpha1.ChangeActionDelete, obj, nil)
+	return err
+}
+
+// dependsOnCreatedObject returns true if creating the given object failed only because it depends on an object that
+// isn't actually created in dry-run mode.
+func (c *DryRunClient) dependsOnCreatedObject(obj client.Object, err error) bool {
+	namespace := dryRunObject{gvk: corev1.SchemeGroupVersion.WithKind("Namespace"), key: client.ObjectKey{Name: obj.GetNamespace()}}
+	return meta.IsNoMatchError(err) || (apierrors.IsNotFound(err) && c.created.Has(namespace))
+}
+
+func (c *DryRunClient) recordUpdate(ctx context.Context, obj client.Object, update func() error) error {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return err
+	}
+
+	if err := update(); err != nil {
+		return err
+	}
+
+	fields, err := changedFields(current, obj)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	_, err = c.record(fluxv1alpha1.ChangeActionUpdate, obj, fields)
+	return err
+}
+
+func (c *DryRunClient) record(action fluxv1alpha1.ChangeAction, obj client.Object, fields []string) (fluxv1alpha1.ObjectChange, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return fluxv1alpha1.ObjectChange{}, err
+	}
+
+	if len(fields) > maxChangedFields {
+		fields = fields[:maxChangedFields]
+	}
+
+	change := fluxv1alpha1.ObjectChange{
+		Action:     action,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Fields:     fields,
+	}
+	c.changes = append(c.changes, change)
+	return change, nil
+}
+
+// changedFields returns the sorted paths of the fields that differ between the given objects. Lists are compared as a
+// whole. Metadata maintained by the API server and the status are ignored.
+func changedFields(oldObj, newObj runtime.Object) ([]string, error) {
+	oldContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, content := range []map[string]any{oldContent, newContent} {
+		delete(content, "status")
+		for _, field := range []string{"resourceVersion", "generation", "managedFields", "creationTimestamp", "uid"} {
+			unstructured.RemoveNestedField(content, "metadata", field)
+		}
+	}
+
+	var fields []string
+	diffFields(&fields, "", oldContent, newContent)
+	slices.Sort(fields)
+	return fields, nil
+}
+
+func diffFields(fields *[]string, prefix string, oldValue, newValue any) {
+	oldMap, oldIsMap := oldValue.(map[string]any)
+	newMap, newIsMap := newValue.(map[string]any)
+	if !oldIsMap || !newIsMap {
+		if !apiequality.Semantic.DeepEqual(oldValue, newValue) {
+			*fields = append(*fields, prefix)
+		}
+		return
+	}
+
+	keys := sets.New(slices.Collect(maps.Keys(oldMap))...).Insert(slices.Collect(maps.Keys(newMap))...)
+	for _, key := range sets.List(keys) {
+		diffFields(fields, strings.TrimPrefix(prefix+"."+key, "."), oldMap[key], newMap[key])
+	}
+}
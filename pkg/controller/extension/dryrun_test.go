@@ -0,0 +1,259 @@
+package extension
+
+import (
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/extensions"
+	. "github.com/gardener/gardener/pkg/utils/test/matchers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("dryRun", func() {
+	var (
+		seedClient   client.Client
+		shootClient  client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+		a            *actuator
+
+		ext           *extensionsv1alpha1.Extension
+		config        *fluxv1alpha1.FluxConfig
+		cluster       *extensions.Cluster
+		gitRepo       *sourcev1.GitRepository
+		kustomization *kustomizev1.Kustomization
+	)
+
+	BeforeEach(func() {
+		seedClient = newSeedClient()
+		shootClient = newShootClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		a = NewActuator(seedClient, fakeRecorder, "garden-id", DefaultBootstrapOptions(), setupManifestsSource("v2.1.3"), nil, nil, nil).(*actuator)
+
+		ext = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shoot-flux",
+				Namespace: "shoot--foo--bar",
+			},
+		}
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		recorder = NewEventRecorder(fakeRecorder, ext)
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: sourcev1.GitRepositorySpec{
+				URL: "http://example.com",
+			},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Path: "/some/path",
+			},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Flux: &fluxv1alpha1.FluxInstallation{
+				Version:   ptr.To("v2.1.3"),
+				Registry:  ptr.To("reg.example.com"),
+				Namespace: ptr.To("flux-system"),
+			},
+			Source: &fluxv1alpha1.Source{
+				Template: encodeSourceObject(gitRepo.DeepCopy()),
+			},
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: *kustomization.DeepCopy(),
+			},
+			DryRun: ptr.To(true),
+		}
+		cluster = &extensions.Cluster{
+			Shoot: &gardencorev1beta1.Shoot{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar"},
+				Status: gardencorev1beta1.ShootStatus{
+					TechnicalID:     "shoot--foo--bar",
+					ClusterIdentity: ptr.To("cluster-identity"),
+				},
+			},
+		}
+	})
+
+	dryRunStatus := func() *fluxv1alpha1.DryRunStatus {
+		GinkgoHelper()
+		Expect(seedClient.Get(ctx, client.ObjectKeyFromObject(ext), ext)).To(Succeed())
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Bootstrap).To(BeNil(), "dry run should not progress the bootstrap")
+		return status.DryRun
+	}
+
+	change := func(action fluxv1alpha1.ChangeAction, kind, namespace, name string) OmegaMatcher {
+		return MatchFields(IgnoreExtras, Fields{
+			"Action":    Equal(action),
+			"Kind":      Equal(kind),
+			"Namespace": Equal(namespace),
+			"Name":      Equal(name),
+		})
+	}
+
+	// The fake client's RESTMapper doesn't know which kinds are cluster-scoped, so the applier of the install manifest
+	// sets the default namespace on cluster-scoped RBAC objects.
+	clusterScopedChange := func(action fluxv1alpha1.ChangeAction, kind, name string) OmegaMatcher {
+		return MatchFields(IgnoreExtras, Fields{
+			"Action": Equal(action),
+			"Kind":   Equal(kind),
+			"Name":   Equal(name),
+		})
+	}
+
+	It("should report the objects that would be created without creating them", func() {
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+
+		// every object is reported once, even if it is created by several steps like the Flux namespace
+		Expect(dryRunStatus().Changes).To(ConsistOf(
+			change(fluxv1alpha1.ChangeActionCreate, "Namespace", "", "flux-system"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "alerts.notification.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "buckets.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "externalartifacts.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "gitrepositories.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "helmcharts.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "helmreleases.helm.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "helmrepositories.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "kustomizations.kustomize.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "ocirepositories.source.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "providers.notification.toolkit.fluxcd.io"),
+			change(fluxv1alpha1.ChangeActionCreate, "CustomResourceDefinition", "", "receivers.notification.toolkit.fluxcd.io"),
+			clusterScopedChange(fluxv1alpha1.ChangeActionCreate, "ClusterRole", "crd-controller-flux-system"),
+			clusterScopedChange(fluxv1alpha1.ChangeActionCreate, "ClusterRole", "flux-edit-flux-system"),
+			clusterScopedChange(fluxv1alpha1.ChangeActionCreate, "ClusterRole", "flux-view-flux-system"),
+			clusterScopedChange(fluxv1alpha1.ChangeActionCreate, "ClusterRoleBinding", "cluster-reconciler-flux-system"),
+			clusterScopedChange(fluxv1alpha1.ChangeActionCreate, "ClusterRoleBinding", "crd-controller-flux-system"),
+			change(fluxv1alpha1.ChangeActionCreate, "ResourceQuota", "flux-system", "critical-pods-flux-system"),
+			change(fluxv1alpha1.ChangeActionCreate, "NetworkPolicy", "flux-system", "allow-egress"),
+			change(fluxv1alpha1.ChangeActionCreate, "NetworkPolicy", "flux-system", "allow-scraping"),
+			change(fluxv1alpha1.ChangeActionCreate, "NetworkPolicy", "flux-system", "allow-webhooks"),
+			change(fluxv1alpha1.ChangeActionCreate, "ServiceAccount", "flux-system", "helm-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "ServiceAccount", "flux-system", "kustomize-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "ServiceAccount", "flux-system", "notification-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "ServiceAccount", "flux-system", "source-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Service", "flux-system", "notification-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Service", "flux-system", "source-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Service", "flux-system", "webhook-receiver"),
+			change(fluxv1alpha1.ChangeActionCreate, "Deployment", "flux-system", "helm-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Deployment", "flux-system", "kustomize-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Deployment", "flux-system", "notification-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "Deployment", "flux-system", "source-controller"),
+			change(fluxv1alpha1.ChangeActionCreate, "GitRepository", "flux-system", "flux-system"),
+			change(fluxv1alpha1.ChangeActionCreate, "Kustomization", "flux-system", "flux-system"),
+			change(fluxv1alpha1.ChangeActionCreate, "ConfigMap", "flux-system", shootInfoConfigMapName),
+		))
+		Expect(fakeRecorder.Events).To(Receive(HavePrefix("Normal DryRunCompleted Dry run completed: ")))
+
+		Expect(shootClient.Get(ctx, client.ObjectKey{Namespace: "flux-system", Name: "source-controller"}, &appsv1.Deployment{})).To(BeNotFoundError())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), &sourcev1.GitRepository{})).To(BeNotFoundError())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(kustomization), &kustomizev1.Kustomization{})).To(BeNotFoundError())
+	})
+
+	It("should report the changed fields of existing objects", func() {
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(InstallFlux(ctx, log, shootClient, config.Flux, a.manifests, nil, nil)).To(Succeed())
+		Expect(shootClient.Create(ctx, gitRepo)).To(Succeed())
+		Expect(shootClient.Create(ctx, kustomization)).To(Succeed())
+		Expect(ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, a.gardenClusterIdentity)).To(Succeed())
+		Expect(shootClient.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale",
+			Namespace: "flux-system",
+			Labels:    map[string]string{managedByLabelKey: managedByLabelValue},
+		}})).To(Succeed())
+
+		config.Kustomization.Template.Spec.Path = "/other/path"
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+
+		// The fake client doesn't default objects like the API server, so objects of the install manifest show changes
+		// for all fields that are defaulted on conversion. Only check the bootstrapped objects.
+		changes := dryRunStatus().Changes
+		Expect(changes).To(ContainElements(
+			MatchFields(IgnoreExtras, Fields{
+				"Action":     Equal(fluxv1alpha1.ChangeActionUpdate),
+				"APIVersion": Equal(kustomizev1.GroupVersion.String()),
+				"Kind":       Equal("Kustomization"),
+				"Fields":     ConsistOf("spec.path"),
+			}),
+			change(fluxv1alpha1.ChangeActionDelete, "Secret", "flux-system", "stale"),
+		))
+		Expect(changes).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{"Kind": Equal("GitRepository")})))
+		Expect(changes).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{"Name": Equal(shootInfoConfigMapName)})))
+
+		kustomization := &kustomizev1.Kustomization{}
+		Expect(shootClient.Get(ctx, client.ObjectKey{Namespace: "flux-system", Name: "flux-system"}, kustomization)).To(Succeed())
+		Expect(kustomization.Spec.Path).To(Equal("/some/path"))
+		Expect(shootClient.Get(ctx, client.ObjectKey{Namespace: "flux-system", Name: "stale"}, &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should only record an event if the changes differ from the previous dry run", func() {
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(fakeRecorder.Events).To(Receive())
+
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(fakeRecorder.Events).NotTo(Receive())
+		Expect(dryRunStatus()).NotTo(BeNil())
+	})
+
+	It("should remove the dry-run result once the objects are reconciled", func() {
+		Expect(a.dryRun(ctx, log, recorder, shootClient, ext, config, cluster)).To(Succeed())
+		Expect(dryRunStatus()).NotTo(BeNil())
+
+		Expect(a.reportReadiness(ctx, recorder, shootClient, ext, config)).To(Succeed())
+		Expect(dryRunStatus()).To(BeNil())
+	})
+})
+
+var _ = Describe("DryRunClient", func() {
+	It("should not report objects in namespaces that would be created as missing", func() {
+		shootClient := newShootClient()
+		c := NewDryRunClient(shootClient)
+
+		Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}})).To(Succeed())
+		// objects created again are only reported once
+		Expect(c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})).To(Succeed())
+		Expect(c.Changes()).To(HaveExactElements(
+			Equal(fluxv1alpha1.ObjectChange{Action: fluxv1alpha1.ChangeActionCreate, APIVersion: "v1", Kind: "Namespace", Name: "foo"}),
+			Equal(fluxv1alpha1.ObjectChange{Action: fluxv1alpha1.ChangeActionCreate, APIVersion: "v1", Kind: "ConfigMap", Namespace: "foo", Name: "bar"}),
+		))
+
+		Expect(shootClient.Get(ctx, client.ObjectKey{Name: "foo"}, &corev1.Namespace{})).To(BeNotFoundError())
+	})
+
+	It("should not record updates without changes", func() {
+		shootClient := newShootClient()
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}, Data: map[string]string{"foo": "bar"}}
+		Expect(shootClient.Create(ctx, configMap)).To(Succeed())
+
+		c := NewDryRunClient(shootClient)
+		Expect(c.Update(ctx, configMap.DeepCopy())).To(Succeed())
+		Expect(c.Changes()).To(BeEmpty())
+
+		configMap.Data = map[string]string{"foo": "baz", "new": "value"}
+		Expect(c.Update(ctx, configMap)).To(Succeed())
+		Expect(c.Changes()).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Action": Equal(fluxv1alpha1.ChangeActionUpdate),
+			"Fields": HaveExactElements("data.foo", "data.new"),
+		})))
+	})
+})
@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/gardener/gardener/extensions/pkg/util"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// configurationProblemReasons are reasons of Flux conditions that indicate a problem with the configuration of the
+	// bootstrapped objects, e.g., invalid credentials or URLs.
+	configurationProblemReasons = sets.New(
+		sourcev1.AuthenticationFailedReason,
+		sourcev1.URLInvalidReason,
+		sourcev1.InvalidSTSConfigurationReason,
+		sourcev1.InvalidProviderConfigurationReason,
+		fluxmeta.InvalidURLReason,
+		fluxmeta.InvalidPathReason,
+		fluxmeta.InsecureConnectionsDisallowedReason,
+		fluxmeta.UnsupportedConnectionTypeReason,
+	)
+
+	// imagePullReasons are reasons of waiting containers that indicate that the Flux images cannot be pulled.
+	imagePullReasons = sets.New("ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull")
+
+	// knownCodes maps Gardener error codes to matchers for error messages of Flux objects and components.
+	knownCodes = map[gardencorev1beta1.ErrorCode]func(string) bool{
+		gardencorev1beta1.ErrorConfigurationProblem: regexp.MustCompile(`(?i)(authentication required|authentication failed|authorization failed|unable to authenticate|invalid username or password|permission denied \(publickey\)|repository not found|could not read username)`).MatchString,
+		gardencorev1beta1.ErrorInfraDependencies:    regexp.MustCompile(`(ErrImagePull|ImagePullBackOff|InvalidImageName|ErrImageNeverPull)`).MatchString,
+	}
+)
+
+// determineError attaches Gardener error codes to the given error based on its message. Errors that already carry
+// error codes are returned unchanged.
+func determineError(err error) error {
+	return util.DetermineError(err, knownCodes)
+}
+
+// fluxConditionError returns an error for the given failed Flux condition. Gardener error codes are attached based on
+// the condition's reason and message.
+func fluxConditionError(description string, cond *metav1.Condition) error {
+	err := fmt.Errorf("%s: %s", description, cond.Message)
+	if configurationProblemReasons.Has(cond.Reason) {
+		return v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	return determineError(err)
+}
+
+// checkImagePull returns an error if any container of the given Deployment's pods is waiting because its image cannot
+// be pulled.
+func checkImagePull(ctx context.Context, c client.Reader, deployment *appsv1.Deployment) error {
+	if deployment.Spec.Selector == nil {
+		return nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(deployment.Namespace), client.MatchingLabels(deployment.Spec.Selector.MatchLabels)); err != nil {
+		return fmt.Errorf("failed to list pods of deployment %q: %w", client.ObjectKeyFromObject(deployment), err)
+	}
+
+	for _, pod := range podList.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if waiting := status.State.Waiting; waiting != nil && imagePullReasons.Has(waiting.Reason) {
+				return fmt.Errorf("container %q of pod %q is waiting: %s: %s", status.Name, client.ObjectKeyFromObject(&pod), waiting.Reason, waiting.Message)
+			}
+		}
+	}
+
+	return nil
+}
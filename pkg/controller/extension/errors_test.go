@@ -0,0 +1,148 @@
+package extension
+
+import (
+	"errors"
+
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("CheckFluxObject", func() {
+	var gitRepo *sourcev1.GitRepository
+
+	BeforeEach(func() {
+		gitRepo = &sourcev1.GitRepository{}
+	})
+
+	It("should not be done if the object has not been reconciled yet", func() {
+		done, err := CheckFluxObject(gitRepo)()
+		Expect(done).To(BeFalse())
+		Expect(err).To(MatchError("has not been reconciled yet"))
+	})
+
+	It("should attach a configuration problem code based on the reason", func() {
+		gitRepo.SetConditions([]metav1.Condition{{
+			Type:    fluxmeta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  sourcev1.AuthenticationFailedReason,
+			Message: "failed to checkout",
+		}})
+
+		done, err := CheckFluxObject(gitRepo)()
+		Expect(done).To(BeTrue())
+		Expect(err).To(MatchError("reconciliation failed: failed to checkout"))
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+	})
+
+	It("should attach a configuration problem code based on the message", func() {
+		gitRepo.SetConditions([]metav1.Condition{{
+			Type:    fluxmeta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  sourcev1.GitOperationFailedReason,
+			Message: "failed to checkout and determine revision: unable to clone: authentication required",
+		}})
+
+		done, err := CheckFluxObject(gitRepo)()
+		Expect(done).To(BeTrue())
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+	})
+
+	It("should not attach error codes for unknown failures", func() {
+		gitRepo.SetConditions([]metav1.Condition{{
+			Type:    fluxmeta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  sourcev1.GitOperationFailedReason,
+			Message: "connection reset by peer",
+		}})
+
+		done, err := CheckFluxObject(gitRepo)()
+		Expect(done).To(BeTrue())
+		Expect(err).To(HaveOccurred())
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(BeEmpty())
+	})
+
+	It("should fail if the object is stalled", func() {
+		gitRepo.SetConditions([]metav1.Condition{
+			{
+				Type:   fluxmeta.ReadyCondition,
+				Status: metav1.ConditionUnknown,
+			},
+			{
+				Type:    fluxmeta.StalledCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  fluxmeta.InvalidURLReason,
+				Message: "invalid URL",
+			},
+		})
+
+		done, err := CheckFluxObject(gitRepo)()
+		Expect(done).To(BeTrue())
+		Expect(err).To(MatchError("reconciliation stalled: invalid URL"))
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+	})
+})
+
+var _ = Describe("determineError", func() {
+	It("should attach an infrastructure dependencies code for image pull problems", func() {
+		err := determineError(errors.New(`container "manager" is waiting: ImagePullBackOff: Back-off pulling image`))
+		Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorInfraDependencies))
+	})
+})
+
+var _ = Describe("checkImagePull", func() {
+	var (
+		shootClient client.Client
+		deployment  *appsv1.Deployment
+		pod         *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		shootClient = newShootClient()
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "source-controller", Namespace: "flux-system"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "source-controller"}},
+			},
+		}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source-controller-abc",
+				Namespace: "flux-system",
+				Labels:    map[string]string{"app": "source-controller"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "manager",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image",
+					}},
+				}},
+			},
+		}
+	})
+
+	It("should report containers that cannot pull their image", func() {
+		Expect(shootClient.Create(ctx, pod)).To(Succeed())
+
+		err := checkImagePull(ctx, shootClient, deployment)
+		Expect(err).To(MatchError(ContainSubstring(`container "manager" of pod "flux-system/source-controller-abc" is waiting: ImagePullBackOff`)))
+		Expect(v1beta1helper.ExtractErrorCodes(determineError(err))).To(ConsistOf(gardencorev1beta1.ErrorInfraDependencies))
+	})
+
+	It("should ignore other waiting reasons", func() {
+		pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ContainerCreating"
+		Expect(shootClient.Create(ctx, pod)).To(Succeed())
+
+		Expect(checkImagePull(ctx, shootClient, deployment)).To(Succeed())
+	})
+})
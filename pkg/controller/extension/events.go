@@ -0,0 +1,103 @@
+package extension
+
+import (
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+// Reasons of the events recorded on the Extension object.
+const (
+	// EventReasonInstallingFlux is the reason of the event recorded when the Flux installation is started.
+	EventReasonInstallingFlux = "InstallingFlux"
+	// EventReasonFluxInstalled is the reason of the event recorded when the Flux installation is ready.
+	EventReasonFluxInstalled = "FluxInstalled"
+	// EventReasonSourceCreated is the reason of the event recorded when the source object is created.
+	EventReasonSourceCreated = "SourceCreated"
+	// EventReasonSourceReady is the reason of the event recorded when the source object is ready.
+	EventReasonSourceReady = "SourceReady"
+	// EventReasonSourceNotReady is the reason of the event recorded when a source object that is not waited for is not
+	// ready.
+	EventReasonSourceNotReady = "SourceNotReady"
+	// EventReasonKustomizationCreated is the reason of the event recorded when the Kustomization is created.
+	EventReasonKustomizationCreated = "KustomizationCreated"
+	// EventReasonKustomizationReady is the reason of the event recorded when the Kustomization is ready.
+	EventReasonKustomizationReady = "KustomizationReady"
+	// EventReasonKustomizationNotReady is the reason of the event recorded when a Kustomization that is not waited for
+	// is not ready.
+	EventReasonKustomizationNotReady = "KustomizationNotReady"
+	// EventReasonWaitingForBootstrapPhase is the reason of the event recorded while waiting for a bootstrap phase.
+	EventReasonWaitingForBootstrapPhase = "WaitingForBootstrapPhase"
+	// EventReasonBootstrapFailed is the reason of the event recorded when a bootstrap phase failed.
+	EventReasonBootstrapFailed = "BootstrapFailed"
+	// EventReasonBootstrapTimedOut is the reason of the event recorded when a bootstrap phase timed out.
+	EventReasonBootstrapTimedOut = "BootstrapTimedOut"
+	// EventReasonBootstrapCompleted is the reason of the event recorded when the bootstrap is completed.
+	EventReasonBootstrapCompleted = "BootstrapCompleted"
+	// EventReasonSecretSynced is the reason of the event recorded when a Secret is created or updated in the shoot.
+	EventReasonSecretSynced = "SecretSynced"
+	// EventReasonSecretDeleted is the reason of the event recorded when a Secret is deleted from the shoot.
+	EventReasonSecretDeleted = "SecretDeleted"
+	// EventReasonConfigMapSynced is the reason of the event recorded when a ConfigMap is created or updated in the
+	// shoot.
+	EventReasonConfigMapSynced = "ConfigMapSynced"
+	// EventReasonConfigMapDeleted is the reason of the event recorded when a ConfigMap is deleted from the shoot.
+	EventReasonConfigMapDeleted = "ConfigMapDeleted"
+	// EventReasonRebootstrapRequested is the reason of the event recorded when the bootstrap is restarted as requested
+	// by the operation annotation.
+	EventReasonRebootstrapRequested = "RebootstrapRequested"
+	// EventReasonDryRunCompleted is the reason of the event recorded when a dry run computed different changes than
+	// the previous one.
+	EventReasonDryRunCompleted = "DryRunCompleted"
+	// EventReasonSuspended is the reason of the event recorded when a bootstrapped object is suspended in the shoot.
+	EventReasonSuspended = "Suspended"
+	// EventReasonResumed is the reason of the event recorded when a bootstrapped object is resumed in the shoot.
+	EventReasonResumed = "Resumed"
+	// EventReasonWokeUp is the reason of the event recorded when the reconciliation of the bootstrapped objects is
+	// requested after the shoot has woken up from hibernation.
+	EventReasonWokeUp = "WokeUp"
+	// EventReasonFluxNotReadyAfterWakeUp is the reason of the event recorded when the Flux controllers don't get ready
+	// after the shoot has woken up from hibernation.
+	EventReasonFluxNotReadyAfterWakeUp = "FluxNotReadyAfterWakeUp"
+)
+
+// Actions of the events recorded on the Extension object.
+const (
+	eventActionInstall   = "Install"
+	eventActionBootstrap = "Bootstrap"
+	eventActionSync      = "Sync"
+	eventActionDelete    = "Delete"
+	eventActionDryRun    = "DryRun"
+	eventActionSuspend   = "Suspend"
+	eventActionResume    = "Resume"
+	eventActionWakeUp    = "WakeUp"
+)
+
+// EventRecorder records events on an Extension object, so that project members can follow the bootstrap and sync of
+// Flux without access to the controller logs on the seed. A nil EventRecorder doesn't record any events.
+type EventRecorder struct {
+	recorder events.EventRecorder
+	ext      *extensionsv1alpha1.Extension
+}
+
+// NewEventRecorder returns an EventRecorder for the given Extension.
+func NewEventRecorder(recorder events.EventRecorder, ext *extensionsv1alpha1.Extension) *EventRecorder {
+	return &EventRecorder{recorder: recorder, ext: ext}
+}
+
+// Normal records an event of type Normal.
+func (r *EventRecorder) Normal(reason, action, note string, args ...any) {
+	r.event(corev1.EventTypeNormal, reason, action, note, args...)
+}
+
+// Warning records an event of type Warning.
+func (r *EventRecorder) Warning(reason, action, note string, args ...any) {
+	r.event(corev1.EventTypeWarning, reason, action, note, args...)
+}
+
+func (r *EventRecorder) event(eventType, reason, action, note string, args ...any) {
+	if r == nil || r.recorder == nil {
+		return
+	}
+	r.recorder.Eventf(r.ext, nil, eventType, reason, action, note, args...)
+}
@@ -17,6 +17,8 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 )
 
 var (
@@ -25,8 +27,7 @@ var (
 )
 
 const (
-	poll    = 100 * time.Millisecond
-	timeout = 1 * time.Second
+	poll = 100 * time.Millisecond
 )
 
 func TestExtension(t *testing.T) {
@@ -41,27 +42,12 @@ var _ = BeforeSuite(func() {
 	SetDefaultEventuallyPollingInterval(poll)
 })
 
-// testAsync runs f() in a goroutine and returns a channel to wait on. E.g.
-//
-//	done := testAsync(func(){ Expect(true).To(BeTrue()) })
-//	Eventually(done).Should(BeClosed())
-//
-// This is used here, since the functions under test are blocking until resources become ready.
-func testAsync(f func()) chan struct{} {
-	done := make(chan struct{})
-	go func() {
-		defer GinkgoRecover()
-		f()
-		close(done)
-	}()
-	return done
-}
-
 func newSeedClient() client.Client {
 	GinkgoHelper()
 	scheme := runtime.NewScheme()
 	Expect((&runtime.SchemeBuilder{
 		extensionsv1alpha1.AddToScheme,
+		fluxv1alpha1.AddToScheme,
 		clientgoscheme.AddToScheme,
 	}).AddToScheme(scheme)).To(Succeed())
 	return fake.NewClientBuilder().
@@ -0,0 +1,141 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/utils/kubernetes/health"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// recordHibernation records the hibernation of the shoot in the Extension's providerStatus, so that the following
+// wake-up can be detected by handleWakeUp.
+func (a *actuator) recordHibernation(ctx context.Context, ext *extensionsv1alpha1.Extension) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+	if status.Hibernation != nil && status.Hibernation.Hibernated {
+		return nil
+	}
+
+	status.Hibernation = &fluxv1alpha1.HibernationStatus{
+		Hibernated:         true,
+		LastTransitionTime: metav1.Now(),
+	}
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error recording hibernation in Extension status: %w", err)
+	}
+	return nil
+}
+
+// handleWakeUp requests an immediate reconciliation of the bootstrapped source and Kustomization after the shoot has
+// woken up from hibernation, as Flux is often in exponential backoff after a long hibernation. It waits for the Flux
+// controllers to get ready by returning a RequeueAfterError within the install timeout of the bootstrap. If they don't
+// get ready in time, a warning event is recorded and the wake-up is not handled any further. If Flux has not been
+// bootstrapped yet, the hibernation is only removed from the status.
+func (a *actuator) handleWakeUp(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	shootClient client.Client,
+	ext *extensionsv1alpha1.Extension,
+	config *fluxv1alpha1.FluxConfig,
+) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+	if status.Hibernation == nil {
+		return nil
+	}
+
+	if !IsFluxBootstrapped(ext) {
+		return a.finishWakeUp(ctx, ext, status)
+	}
+
+	if status.Hibernation.Hibernated {
+		log.Info("Shoot has woken up from hibernation")
+		status.Hibernation = &fluxv1alpha1.HibernationStatus{
+			Hibernated:         false,
+			LastTransitionTime: metav1.Now(),
+		}
+		if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+			return fmt.Errorf("error recording wake-up in Extension status: %w", err)
+		}
+	}
+
+	settings := a.bootstrapOptions.settingsFor(config)
+	if err := CheckFluxControllers(ctx, shootClient, config.Flux); err != nil {
+		if time.Since(status.Hibernation.LastTransitionTime.Time) < settings.installTimeout {
+			log.Info("Waiting for Flux controllers to get ready after wake-up", "reason", err.Error())
+			return &reconcilerutils.RequeueAfterError{RequeueAfter: settings.pollInterval, Cause: fmt.Errorf("error waiting for Flux controllers after wake-up: %w", err)}
+		}
+
+		recorder.Warning(EventReasonFluxNotReadyAfterWakeUp, eventActionWakeUp, "Flux controllers are not ready %s after the wake-up from hibernation: %v", settings.installTimeout, err)
+		return a.finishWakeUp(ctx, ext, status)
+	}
+
+	objects, err := bootstrapObjects(config)
+	if err != nil {
+		return err
+	}
+	var requested []string
+	for _, object := range objects {
+		found, err := requestReconciliation(ctx, shootClient, object)
+		if err != nil {
+			return err
+		}
+		if found {
+			requested = append(requested, object.String())
+		}
+	}
+	if len(requested) > 0 {
+		log.Info("Requested reconciliation of Flux objects after wake-up", "objects", requested)
+		recorder.Normal(EventReasonWokeUp, eventActionWakeUp, "Flux controllers are ready after the wake-up from hibernation, requested reconciliation of %s", strings.Join(requested, ", "))
+	}
+
+	return a.finishWakeUp(ctx, ext, status)
+}
+
+// finishWakeUp removes the hibernation from the Extension's providerStatus.
+func (a *actuator) finishWakeUp(ctx context.Context, ext *extensionsv1alpha1.Extension, status *fluxv1alpha1.FluxStatus) error {
+	status.Hibernation = nil
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error removing hibernation from Extension status: %w", err)
+	}
+	return nil
+}
+
+// fluxPartOfLabel is the label selecting the Deployments of the Flux controllers.
+var fluxPartOfLabel = client.MatchingLabels{"app.kubernetes.io/part-of": "flux"}
+
+// CheckFluxControllers checks whether all Deployments of the Flux controllers in the Flux namespace are healthy. It
+// returns an error listing the unhealthy Deployments, or if no Deployment exists.
+func CheckFluxControllers(ctx context.Context, c client.Reader, config *fluxv1alpha1.FluxInstallation) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(*config.Namespace), fluxPartOfLabel); err != nil {
+		return fmt.Errorf("error listing Flux controllers: %w", err)
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Errorf("no Flux controllers found in namespace %q", *config.Namespace)
+	}
+
+	var errs []error
+	for i := range deployments.Items {
+		if err := health.CheckDeployment(&deployments.Items[i]); err != nil {
+			errs = append(errs, fmt.Errorf("deployment %s: %w", deployments.Items[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
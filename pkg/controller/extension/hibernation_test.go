@@ -0,0 +1,207 @@
+package extension
+
+import (
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("handleWakeUp", func() {
+	var (
+		seedClient   client.Client
+		shootClient  client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+		a            *actuator
+
+		ext              *extensionsv1alpha1.Extension
+		config           *fluxv1alpha1.FluxConfig
+		gitRepo          *sourcev1.GitRepository
+		kustomization    *kustomizev1.Kustomization
+		sourceController *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		seedClient = newSeedClient()
+		shootClient = newShootClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		a = NewActuator(seedClient, fakeRecorder, "garden-id", DefaultBootstrapOptions(), nil, nil, nil, nil).(*actuator)
+
+		ext = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shoot-flux",
+				Namespace: "shoot--foo--bar",
+			},
+		}
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		recorder = NewEventRecorder(fakeRecorder, ext)
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: sourcev1.GitRepositorySpec{
+				URL: "http://example.com",
+			},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Path: "/some/path",
+			},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Flux: &fluxv1alpha1.FluxInstallation{
+				Namespace: ptr.To("flux-system"),
+			},
+			Source: &fluxv1alpha1.Source{
+				Template: encodeSourceObject(gitRepo.DeepCopy()),
+			},
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: *kustomization.DeepCopy(),
+			},
+		}
+		Expect(BootstrapSource(ctx, log, nil, shootClient, config.Source, false)).To(Succeed())
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, false)).To(Succeed())
+
+		sourceController = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source-controller",
+				Namespace: "flux-system",
+				Labels:    map[string]string{"app.kubernetes.io/part-of": "flux"},
+			},
+		}
+		Expect(shootClient.Create(ctx, sourceController)).To(Succeed())
+
+		Expect(SetFluxBootstrapped(ctx, seedClient, ext)).To(Succeed())
+		Expect(a.recordHibernation(ctx, ext)).To(Succeed())
+	})
+
+	providerStatus := func() *fluxv1alpha1.FluxStatus {
+		GinkgoHelper()
+		Expect(seedClient.Get(ctx, client.ObjectKeyFromObject(ext), ext)).To(Succeed())
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	makeControllerReady := func() {
+		GinkgoHelper()
+		sourceController.Status.ObservedGeneration = sourceController.Generation
+		sourceController.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:   appsv1.DeploymentAvailable,
+			Status: corev1.ConditionTrue,
+		}}
+		Expect(shootClient.Status().Update(ctx, sourceController)).To(Succeed())
+	}
+
+	reconcileRequested := func(obj client.Object) bool {
+		GinkgoHelper()
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		_, ok := obj.GetAnnotations()[fluxmeta.ReconcileRequestAnnotation]
+		return ok
+	}
+
+	It("should record the hibernation only once", func() {
+		status := providerStatus()
+		Expect(status.Hibernation.Hibernated).To(BeTrue())
+		transitionTime := status.Hibernation.LastTransitionTime
+
+		Expect(a.recordHibernation(ctx, ext)).To(Succeed())
+		Expect(providerStatus().Hibernation.LastTransitionTime).To(Equal(transitionTime))
+	})
+
+	It("should do nothing if the shoot has not been hibernated", func() {
+		Expect(a.finishWakeUp(ctx, ext, providerStatus())).To(Succeed())
+
+		Expect(a.handleWakeUp(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		Expect(reconcileRequested(gitRepo)).To(BeFalse())
+		Expect(reconcileRequested(kustomization)).To(BeFalse())
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should wait for the Flux controllers and request the reconciliation of the objects", func() {
+		By("waiting for the Flux controllers")
+		Expect(a.handleWakeUp(ctx, log, recorder, shootClient, ext, config)).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(providerStatus().Hibernation.Hibernated).To(BeFalse())
+		Expect(reconcileRequested(gitRepo)).To(BeFalse())
+
+		By("requesting the reconciliation")
+		makeControllerReady()
+		Expect(a.handleWakeUp(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		Expect(providerStatus().Hibernation).To(BeNil())
+		Expect(reconcileRequested(gitRepo)).To(BeTrue())
+		Expect(reconcileRequested(kustomization)).To(BeTrue())
+		Expect(<-fakeRecorder.Events).To(Equal("Normal WokeUp Flux controllers are ready after the wake-up from hibernation, " +
+			"requested reconciliation of GitRepository flux-system/flux-system, Kustomization flux-system/flux-system"))
+	})
+
+	It("should give up if the Flux controllers don't get ready in time", func() {
+		status := providerStatus()
+		status.Hibernation = &fluxv1alpha1.HibernationStatus{LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+		Expect(a.updateProviderStatus(ctx, ext, status)).To(Succeed())
+
+		Expect(a.handleWakeUp(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		Expect(providerStatus().Hibernation).To(BeNil())
+		Expect(reconcileRequested(gitRepo)).To(BeFalse())
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Warning FluxNotReadyAfterWakeUp Flux controllers are not ready"))
+	})
+
+	It("should only remove the hibernation if Flux has not been bootstrapped", func() {
+		ext.Status.Conditions = nil
+
+		Expect(a.handleWakeUp(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		Expect(providerStatus().Hibernation).To(BeNil())
+		Expect(reconcileRequested(gitRepo)).To(BeFalse())
+	})
+})
+
+var _ = Describe("CheckFluxControllers", func() {
+	var (
+		shootClient client.Client
+		config      *fluxv1alpha1.FluxInstallation
+	)
+
+	BeforeEach(func() {
+		shootClient = newShootClient()
+		config = &fluxv1alpha1.FluxInstallation{Namespace: ptr.To("flux-system")}
+	})
+
+	It("should fail if no Flux controllers exist", func() {
+		Expect(CheckFluxControllers(ctx, shootClient, config)).To(MatchError(ContainSubstring("no Flux controllers found")))
+	})
+
+	It("should report unhealthy Flux controllers", func() {
+		for _, name := range []string{"source-controller", "kustomize-controller"} {
+			Expect(shootClient.Create(ctx, &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "flux-system",
+					Labels:    map[string]string{"app.kubernetes.io/part-of": "flux"},
+				},
+			})).To(Succeed())
+		}
+
+		err := CheckFluxControllers(ctx, shootClient, config)
+		Expect(err).To(MatchError(ContainSubstring("deployment kustomize-controller")))
+		Expect(err).To(MatchError(ContainSubstring("deployment source-controller")))
+	})
+})
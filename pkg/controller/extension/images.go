@@ -0,0 +1,144 @@
+package extension
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+)
+
+// ErrMissingImageDigest is returned by RewriteImages if the digest of an image should be pinned, but the digest catalog
+// doesn't contain it.
+var ErrMissingImageDigest = errors.New("digest catalog does not contain a digest")
+
+// RewriteImages applies the image overrides and digest pinning of the given configuration to the containers of the
+// Flux component Deployments in the install manifest. The Deployments are named after the components. It returns a new
+// manifest and leaves the given one untouched, as it might be shared by the manifest cache.
+func RewriteImages(manifest []byte, config *fluxv1alpha1.FluxInstallation, digests fluxmanifests.DigestCatalog) ([]byte, error) {
+	pinDigests := ptr.Deref(config.PinImageDigests, false)
+	if len(config.Images) == 0 && !pinDigests {
+		return manifest, nil
+	}
+
+	overrides := make(map[string]fluxv1alpha1.ComponentImage, len(config.Images))
+	for _, image := range config.Images {
+		overrides[image.Component] = image
+	}
+
+	var (
+		out    bytes.Buffer
+		reader = utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	)
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading install manifest: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, fmt.Errorf("error decoding install manifest: %w", err)
+		}
+		if obj.GetKind() == "Deployment" {
+			component := obj.GetName()
+			override, hasOverride := overrides[component]
+			delete(overrides, component)
+
+			if doc, err = rewriteDeploymentImages(obj, component, override, hasOverride, pinDigests, digests); err != nil {
+				return nil, err
+			}
+		}
+
+		out.WriteString("---\n")
+		out.Write(bytes.TrimPrefix(doc, []byte("---\n")))
+	}
+
+	if len(overrides) > 0 {
+		return nil, fmt.Errorf("image overrides for components %v do not match any installed component", slices.Sorted(maps.Keys(overrides)))
+	}
+
+	return out.Bytes(), nil
+}
+
+func rewriteDeploymentImages(
+	obj *unstructured.Unstructured,
+	component string,
+	override fluxv1alpha1.ComponentImage,
+	hasOverride, pinDigests bool,
+	digests fluxmanifests.DigestCatalog,
+) ([]byte, error) {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return nil, fmt.Errorf("error reading containers of Deployment %s: %w", component, err)
+	}
+
+	for i, c := range containers {
+		container, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		image, ok := container["image"].(string)
+		if !ok {
+			continue
+		}
+
+		repository, tag, digest := parseImage(image)
+		if hasOverride {
+			repository = ptr.Deref(override.Repository, repository)
+			tag = ptr.Deref(override.Tag, tag)
+			digest = ptr.Deref(override.Digest, digest)
+		}
+		if pinDigests && digest == "" {
+			if digest, ok = digests.Digest(component, tag); !ok {
+				return nil, fmt.Errorf("%w for image %s:%s of component %s", ErrMissingImageDigest, repository, tag, component)
+			}
+		}
+
+		container["image"] = formatImage(repository, tag, digest)
+		containers[i] = container
+	}
+
+	if err := unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return nil, fmt.Errorf("error updating containers of Deployment %s: %w", component, err)
+	}
+	return yaml.Marshal(obj.Object)
+}
+
+// parseImage splits an image reference into repository, tag, and digest. The tag and digest may be empty.
+func parseImage(image string) (repository, tag, digest string) {
+	repository, digest, _ = strings.Cut(image, "@")
+	// the repository may contain a registry port, so only consider a colon after the last slash as tag separator
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return repository, tag, digest
+}
+
+func formatImage(repository, tag, digest string) string {
+	image := repository
+	if tag != "" {
+		image += ":" + tag
+	}
+	if digest != "" {
+		image += "@" + digest
+	}
+	return image
+}
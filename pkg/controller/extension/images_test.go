@@ -0,0 +1,123 @@
+package extension
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+)
+
+var _ = Describe("RewriteImages", func() {
+	const manifest = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: flux-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: source-controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/source-controller:v1.9.3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kustomize-controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: registry.example.com:5000/fluxcd/kustomize-controller:v1.9.3
+`
+
+	var (
+		config  *fluxv1alpha1.FluxInstallation
+		digests fluxmanifests.DigestCatalog
+
+		sourceDigest    = "sha256:" + strings.Repeat("a", 64)
+		kustomizeDigest = "sha256:" + strings.Repeat("b", 64)
+	)
+
+	BeforeEach(func() {
+		config = &fluxv1alpha1.FluxInstallation{}
+		digests = fluxmanifests.DigestCatalog{
+			"source-controller:v1.9.3":    sourceDigest,
+			"kustomize-controller:v1.9.3": kustomizeDigest,
+		}
+	})
+
+	It("should return the manifest unchanged if nothing is configured", func() {
+		Expect(RewriteImages([]byte(manifest), config, digests)).To(BeEquivalentTo(manifest))
+	})
+
+	It("should apply the image overrides", func() {
+		config.Images = []fluxv1alpha1.ComponentImage{
+			{Component: "source-controller", Repository: ptr.To("mirror.example.com/flux/source"), Tag: ptr.To("v1.9.4")},
+			{Component: "kustomize-controller", Digest: ptr.To(kustomizeDigest)},
+		}
+
+		out, err := RewriteImages([]byte(manifest), config, digests)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(And(
+			ContainSubstring("image: mirror.example.com/flux/source:v1.9.4\n"),
+			ContainSubstring("image: registry.example.com:5000/fluxcd/kustomize-controller:v1.9.3@"+kustomizeDigest+"\n"),
+			ContainSubstring("kind: Namespace"),
+		))
+	})
+
+	It("should pin the images to the digests from the catalog", func() {
+		config.PinImageDigests = ptr.To(true)
+
+		out, err := RewriteImages([]byte(manifest), config, digests)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(And(
+			ContainSubstring("image: ghcr.io/fluxcd/source-controller:v1.9.3@"+sourceDigest+"\n"),
+			ContainSubstring("image: registry.example.com:5000/fluxcd/kustomize-controller:v1.9.3@"+kustomizeDigest+"\n"),
+		))
+	})
+
+	It("should prefer the digests of the image overrides", func() {
+		config.PinImageDigests = ptr.To(true)
+		overrideDigest := "sha256:" + strings.Repeat("c", 64)
+		config.Images = []fluxv1alpha1.ComponentImage{{Component: "source-controller", Digest: ptr.To(overrideDigest)}}
+
+		Expect(RewriteImages([]byte(manifest), config, digests)).To(
+			WithTransform(func(b []byte) string { return string(b) }, ContainSubstring("image: ghcr.io/fluxcd/source-controller:v1.9.3@"+overrideDigest+"\n")),
+		)
+	})
+
+	It("should fail if the catalog doesn't contain a digest", func() {
+		config.PinImageDigests = ptr.To(true)
+		config.Images = []fluxv1alpha1.ComponentImage{{Component: "source-controller", Tag: ptr.To("v1.9.4")}}
+
+		_, err := RewriteImages([]byte(manifest), config, digests)
+		Expect(err).To(MatchError("digest catalog does not contain a digest for image ghcr.io/fluxcd/source-controller:v1.9.4 of component source-controller"))
+	})
+
+	It("should fail if an override doesn't match any component", func() {
+		config.Images = []fluxv1alpha1.ComponentImage{{Component: "helm-controller", Tag: ptr.To("v1.0.0")}}
+
+		_, err := RewriteImages([]byte(manifest), config, digests)
+		Expect(err).To(MatchError("image overrides for components [helm-controller] do not match any installed component"))
+	})
+
+	It("should not modify the given manifest", func() {
+		config.PinImageDigests = ptr.To(true)
+		in := []byte(manifest)
+
+		_, err := RewriteImages(in, config, digests)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(in).To(BeEquivalentTo(manifest))
+	})
+})
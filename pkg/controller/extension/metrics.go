@@ -0,0 +1,155 @@
+package extension
+
+import (
+	"context"
+	"time"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+const metricsNamespace = "shoot_flux"
+
+// Results of a bootstrap recorded in the bootstrapsTotal metric.
+const (
+	bootstrapResultSucceeded = "succeeded"
+	bootstrapResultFailed    = "failed"
+	bootstrapResultTimedOut  = "timed_out"
+)
+
+var (
+	bootstrapPhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "bootstrap_phase_duration_seconds",
+		Help:      "Duration of successfully completed bootstrap phases.",
+		Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 900, 1800},
+	}, []string{"phase"})
+
+	bootstrapsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "bootstraps_total",
+		Help:      "Number of completed, failed and timed out bootstraps. Failures are labeled by the bootstrap phase and the Gardener error code.",
+	}, []string{"result", "phase", "reason"})
+
+	secretSyncOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "secret_sync_operations_total",
+		Help:      "Number of Secrets created, updated and deleted in shoots.",
+	}, []string{"operation"})
+
+	bootstrappedShootsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "bootstrapped_shoots"),
+		"Number of shoots with a bootstrapped Flux installation by the installed Flux version.",
+		[]string{"version"}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		bootstrapPhaseDuration,
+		bootstrapsTotal,
+		secretSyncOperationsTotal,
+	)
+}
+
+// observeBootstrapPhase records the duration of the given completed bootstrap phase.
+func observeBootstrapPhase(status *fluxv1alpha1.BootstrapStatus) {
+	bootstrapPhaseDuration.WithLabelValues(string(status.Phase)).Observe(time.Since(status.LastTransitionTime.Time).Seconds())
+}
+
+// recordBootstrapResult records the result of a bootstrap. For failures, the reason is the first Gardener error code of
+// the given error, if any.
+func recordBootstrapResult(result string, phase fluxv1alpha1.BootstrapPhase, err error) {
+	reason := ""
+	if codes := v1beta1helper.ExtractErrorCodes(err); len(codes) > 0 {
+		reason = string(codes[0])
+	}
+	bootstrapsTotal.WithLabelValues(result, string(phase), reason).Inc()
+}
+
+// secretSyncOperationDeleted is the operation recorded in the secretSyncOperationsTotal metric for deleted Secrets.
+const secretSyncOperationDeleted = "deleted"
+
+// recordSecretSync records a create, update or delete operation on a Secret in the shoot. Operations sent through a
+// DryRunClient are not recorded, as they are never applied.
+func recordSecretSync(shootClient client.Client, operation string) {
+	if _, ok := shootClient.(*DryRunClient); ok {
+		return
+	}
+	secretSyncOperationsTotal.WithLabelValues(operation).Inc()
+}
+
+// bootstrappedShootsCollectTimeout is the timeout for listing the Extensions when collecting the bootstrappedShoots
+// metric.
+const bootstrappedShootsCollectTimeout = 10 * time.Second
+
+// bootstrappedShootsCollector collects the bootstrapped_shoots metric from the Extensions of this type. The metric is
+// computed on every scrape, so it is correct after restarts and leader changes, and it covers shoots that are not
+// reconciled by this replica.
+type bootstrappedShootsCollector struct {
+	reader  client.Reader
+	decoder runtime.Decoder
+}
+
+// newBootstrappedShootsCollector returns a collector for the bootstrapped_shoots metric that lists the Extensions with
+// the given reader, typically the cached manager client.
+func newBootstrappedShootsCollector(reader client.Reader, scheme *runtime.Scheme) *bootstrappedShootsCollector {
+	return &bootstrappedShootsCollector{
+		reader:  reader,
+		decoder: serializer.NewCodecFactory(scheme).UniversalDecoder(),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *bootstrappedShootsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bootstrappedShootsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *bootstrappedShootsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), bootstrappedShootsCollectTimeout)
+	defer cancel()
+
+	extensionList := &extensionsv1alpha1.ExtensionList{}
+	if err := c.reader.List(ctx, extensionList); err != nil {
+		ch <- prometheus.NewInvalidMetric(bootstrappedShootsDesc, err)
+		return
+	}
+
+	versions := map[string]int{}
+	for i := range extensionList.Items {
+		ext := &extensionList.Items[i]
+		if ext.Spec.Type != fluxv1alpha1.ExtensionType || ext.DeletionTimestamp != nil || !IsFluxBootstrapped(ext) {
+			continue
+		}
+		versions[c.installedVersion(ext)]++
+	}
+
+	for version, count := range versions {
+		ch <- prometheus.MustNewConstMetric(bootstrappedShootsDesc, prometheus.GaugeValue, float64(count), version)
+	}
+}
+
+// bootstrappedShootsUnknownVersion is the version label of shoots that were bootstrapped before the installed version
+// was recorded in the providerStatus. The configured version is not used instead, as it might be a version constraint
+// or might have changed since the bootstrap.
+const bootstrappedShootsUnknownVersion = "unknown"
+
+// installedVersion returns the Flux version installed in the shoot of the given Extension as recorded in the
+// providerStatus, or bootstrappedShootsUnknownVersion if it was not recorded.
+func (c *bootstrappedShootsCollector) installedVersion(ext *extensionsv1alpha1.Extension) string {
+	if ext.Status.ProviderStatus != nil && ext.Status.ProviderStatus.Raw != nil {
+		status := &fluxv1alpha1.FluxStatus{}
+		if err := runtime.DecodeInto(c.decoder, ext.Status.ProviderStatus.Raw, status); err == nil && status.Version != "" {
+			return status.Version
+		}
+	}
+	return bootstrappedShootsUnknownVersion
+}
@@ -0,0 +1,97 @@
+package extension
+
+import (
+	"errors"
+	"strings"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("metrics", func() {
+	Describe("bootstrappedShootsCollector", func() {
+		var (
+			seedClient client.Client
+			collector  *bootstrappedShootsCollector
+		)
+
+		BeforeEach(func() {
+			seedClient = newSeedClient()
+			collector = newBootstrappedShootsCollector(seedClient, seedClient.Scheme())
+		})
+
+		createExtension := func(namespace, extensionType string, bootstrapped bool, status *fluxv1alpha1.FluxStatus, config string) {
+			GinkgoHelper()
+			ext := &extensionsv1alpha1.Extension{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoot-flux", Namespace: namespace},
+				Spec: extensionsv1alpha1.ExtensionSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: extensionType},
+				},
+			}
+			if config != "" {
+				ext.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(config)}
+			}
+			Expect(seedClient.Create(ctx, ext)).To(Succeed())
+
+			if bootstrapped {
+				Expect(SetFluxBootstrapped(ctx, seedClient, ext)).To(Succeed())
+			}
+			if status != nil {
+				var err error
+				ext.Status.ProviderStatus, err = encodeProviderStatus(status)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(seedClient.Status().Update(ctx, ext)).To(Succeed())
+		}
+
+		It("should count the bootstrapped shoots by the installed Flux version", func() {
+			createExtension("shoot--foo--a", fluxv1alpha1.ExtensionType, true, &fluxv1alpha1.FluxStatus{Version: "v2.1.3"}, "")
+			createExtension("shoot--foo--b", fluxv1alpha1.ExtensionType, true, &fluxv1alpha1.FluxStatus{Version: "v2.1.3"}, "")
+			createExtension("shoot--foo--c", fluxv1alpha1.ExtensionType, true, &fluxv1alpha1.FluxStatus{Version: "v2.9.2"}, "")
+			// bootstrapped before the version was recorded in the providerStatus, the configured version is a constraint
+			createExtension("shoot--foo--d", fluxv1alpha1.ExtensionType, true, nil, `{"apiVersion":"flux.extensions.gardener.cloud/v1alpha1","kind":"FluxConfig","flux":{"version":"~2.9"}}`)
+			createExtension("shoot--foo--e", fluxv1alpha1.ExtensionType, false, &fluxv1alpha1.FluxStatus{Version: "v2.9.2"}, "")
+			createExtension("shoot--foo--f", "other", true, &fluxv1alpha1.FluxStatus{Version: "v2.9.2"}, "")
+
+			Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP shoot_flux_bootstrapped_shoots Number of shoots with a bootstrapped Flux installation by the installed Flux version.
+# TYPE shoot_flux_bootstrapped_shoots gauge
+shoot_flux_bootstrapped_shoots{version="v2.1.3"} 2
+shoot_flux_bootstrapped_shoots{version="unknown"} 1
+shoot_flux_bootstrapped_shoots{version="v2.9.2"} 1
+`))).To(Succeed())
+		})
+
+		It("should not report any shoots without Extensions", func() {
+			Expect(testutil.CollectAndCount(collector)).To(Equal(0))
+		})
+	})
+
+	Describe("#recordBootstrapResult", func() {
+		It("should label failures with the Gardener error code", func() {
+			counter := bootstrapsTotal.WithLabelValues(bootstrapResultFailed, string(fluxv1alpha1.BootstrapPhaseSource), string(gardencorev1beta1.ErrorConfigurationProblem))
+			before := testutil.ToFloat64(counter)
+
+			recordBootstrapResult(bootstrapResultFailed, fluxv1alpha1.BootstrapPhaseSource, v1beta1helper.NewErrorWithCodes(errors.New("authentication required"), gardencorev1beta1.ErrorConfigurationProblem))
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+	})
+
+	Describe("#observeBootstrapPhase", func() {
+		It("should observe the duration of the phase", func() {
+			before := testutil.CollectAndCount(bootstrapPhaseDuration)
+
+			observeBootstrapPhase(&fluxv1alpha1.BootstrapStatus{Phase: "Test", LastTransitionTime: metav1.Now()})
+			Expect(testutil.CollectAndCount(bootstrapPhaseDuration)).To(Equal(before + 1))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// BootstrapOptions configures the polling and timeouts of the initial bootstrap of Flux. The defaults can be overridden
+// per shoot in FluxConfig.bootstrap within the configured limits.
+type BootstrapOptions struct {
+	// PollInterval is the default interval in which the readiness of the bootstrapped objects is checked.
+	PollInterval time.Duration
+	// MinPollInterval is the minimum poll interval that can be configured per shoot.
+	MinPollInterval time.Duration
+	// InstallTimeout is the default maximum time to wait for the Flux installation to get ready.
+	InstallTimeout time.Duration
+	// SourceTimeout is the default maximum time to wait for the source object to get ready.
+	SourceTimeout time.Duration
+	// KustomizationTimeout is the default maximum time to wait for the Kustomization to get ready. It is only used if
+	// the Kustomization doesn't specify spec.timeout.
+	KustomizationTimeout time.Duration
+	// MaxTimeout is the maximum timeout that can be configured per shoot.
+	MaxTimeout time.Duration
+}
+
+// DefaultBootstrapOptions returns the default BootstrapOptions.
+func DefaultBootstrapOptions() BootstrapOptions {
+	return BootstrapOptions{
+		PollInterval:         5 * time.Second,
+		MinPollInterval:      time.Second,
+		InstallTimeout:       time.Minute,
+		SourceTimeout:        5 * time.Minute,
+		KustomizationTimeout: 5 * time.Minute,
+		MaxTimeout:           30 * time.Minute,
+	}
+}
+
+// AddFlags adds the flags for the BootstrapOptions to the given FlagSet.
+func (o *BootstrapOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.PollInterval, "bootstrap-poll-interval", o.PollInterval, "Default interval in which the readiness of the bootstrapped Flux objects is checked")
+	fs.DurationVar(&o.MinPollInterval, "bootstrap-min-poll-interval", o.MinPollInterval, "Minimum bootstrap poll interval that can be configured per shoot")
+	fs.DurationVar(&o.InstallTimeout, "bootstrap-install-timeout", o.InstallTimeout, "Default maximum time to wait for the Flux installation to get ready")
+	fs.DurationVar(&o.SourceTimeout, "bootstrap-source-timeout", o.SourceTimeout, "Default maximum time to wait for the Flux source to get ready")
+	fs.DurationVar(&o.KustomizationTimeout, "bootstrap-kustomization-timeout", o.KustomizationTimeout, "Default maximum time to wait for the Flux Kustomization to get ready, if it doesn't specify spec.timeout")
+	fs.DurationVar(&o.MaxTimeout, "bootstrap-max-timeout", o.MaxTimeout, "Maximum bootstrap timeout that can be configured per shoot")
+}
+
+// Validate validates the BootstrapOptions.
+func (o *BootstrapOptions) Validate() error {
+	if o.MinPollInterval <= 0 || o.PollInterval < o.MinPollInterval {
+		return fmt.Errorf("bootstrap poll interval must be at least the minimum poll interval, which must be positive")
+	}
+	for _, timeout := range []time.Duration{o.InstallTimeout, o.SourceTimeout, o.KustomizationTimeout} {
+		if timeout <= 0 || timeout > o.MaxTimeout {
+			return fmt.Errorf("bootstrap timeouts must be positive and must not exceed the maximum timeout %s", o.MaxTimeout)
+		}
+	}
+	return nil
+}
+
+// bootstrapSettings are the effective polling and timeouts for bootstrapping Flux on a single shoot.
+type bootstrapSettings struct {
+	pollInterval         time.Duration
+	installTimeout       time.Duration
+	sourceTimeout        time.Duration
+	kustomizationTimeout time.Duration
+}
+
+// settingsFor returns the effective bootstrap settings for the given FluxConfig. Values configured in
+// FluxConfig.bootstrap take precedence over the Kustomization's spec.timeout and the operator defaults. All values are
+// capped at the configured limits.
+func (o BootstrapOptions) settingsFor(config *fluxv1alpha1.FluxConfig) bootstrapSettings {
+	settings := bootstrapSettings{
+		pollInterval:         o.PollInterval,
+		installTimeout:       o.InstallTimeout,
+		sourceTimeout:        o.SourceTimeout,
+		kustomizationTimeout: o.KustomizationTimeout,
+	}
+
+	if config.Kustomization != nil && config.Kustomization.Template.Spec.Timeout != nil {
+		settings.kustomizationTimeout = config.Kustomization.Template.Spec.Timeout.Duration
+	}
+
+	if bootstrap := config.Bootstrap; bootstrap != nil {
+		if bootstrap.PollInterval != nil {
+			settings.pollInterval = bootstrap.PollInterval.Duration
+		}
+		if bootstrap.InstallTimeout != nil {
+			settings.installTimeout = bootstrap.InstallTimeout.Duration
+		}
+		if bootstrap.SourceTimeout != nil {
+			settings.sourceTimeout = bootstrap.SourceTimeout.Duration
+		}
+		if bootstrap.KustomizationTimeout != nil {
+			settings.kustomizationTimeout = bootstrap.KustomizationTimeout.Duration
+		}
+	}
+
+	settings.pollInterval = max(settings.pollInterval, o.MinPollInterval)
+	settings.installTimeout = min(settings.installTimeout, o.MaxTimeout)
+	settings.sourceTimeout = min(settings.sourceTimeout, o.MaxTimeout)
+	settings.kustomizationTimeout = min(settings.kustomizationTimeout, o.MaxTimeout)
+
+	return settings
+}
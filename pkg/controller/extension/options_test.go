@@ -0,0 +1,86 @@
+package extension
+
+import (
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("BootstrapOptions", func() {
+	var (
+		opts   BootstrapOptions
+		config *fluxv1alpha1.FluxConfig
+	)
+
+	BeforeEach(func() {
+		opts = DefaultBootstrapOptions()
+		config = &fluxv1alpha1.FluxConfig{
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: kustomizev1.Kustomization{},
+			},
+		}
+	})
+
+	Describe("#Validate", func() {
+		It("should accept the defaults", func() {
+			Expect(opts.Validate()).To(Succeed())
+		})
+
+		It("should reject a poll interval below the minimum", func() {
+			opts.PollInterval = opts.MinPollInterval / 2
+			Expect(opts.Validate()).NotTo(Succeed())
+		})
+
+		It("should reject timeouts above the maximum", func() {
+			opts.SourceTimeout = opts.MaxTimeout + time.Second
+			Expect(opts.Validate()).NotTo(Succeed())
+		})
+	})
+
+	Describe("#settingsFor", func() {
+		It("should use the operator defaults", func() {
+			Expect(opts.settingsFor(config)).To(Equal(bootstrapSettings{
+				pollInterval:         5 * time.Second,
+				installTimeout:       time.Minute,
+				sourceTimeout:        5 * time.Minute,
+				kustomizationTimeout: 5 * time.Minute,
+			}))
+		})
+
+		It("should follow the Kustomization's timeout", func() {
+			config.Kustomization.Template.Spec.Timeout = &metav1.Duration{Duration: 12 * time.Minute}
+			Expect(opts.settingsFor(config).kustomizationTimeout).To(Equal(12 * time.Minute))
+		})
+
+		It("should prefer the values configured for the shoot", func() {
+			config.Kustomization.Template.Spec.Timeout = &metav1.Duration{Duration: 12 * time.Minute}
+			config.Bootstrap = &fluxv1alpha1.Bootstrap{
+				PollInterval:         &metav1.Duration{Duration: 10 * time.Second},
+				InstallTimeout:       &metav1.Duration{Duration: 2 * time.Minute},
+				SourceTimeout:        &metav1.Duration{Duration: 15 * time.Minute},
+				KustomizationTimeout: &metav1.Duration{Duration: 20 * time.Minute},
+			}
+			Expect(opts.settingsFor(config)).To(Equal(bootstrapSettings{
+				pollInterval:         10 * time.Second,
+				installTimeout:       2 * time.Minute,
+				sourceTimeout:        15 * time.Minute,
+				kustomizationTimeout: 20 * time.Minute,
+			}))
+		})
+
+		It("should cap the values at the configured limits", func() {
+			config.Bootstrap = &fluxv1alpha1.Bootstrap{
+				PollInterval:  &metav1.Duration{Duration: time.Millisecond},
+				SourceTimeout: &metav1.Duration{Duration: 2 * time.Hour},
+			}
+			settings := opts.settingsFor(config)
+			Expect(settings.pollInterval).To(Equal(opts.MinPollInterval))
+			Expect(settings.sourceTimeout).To(Equal(opts.MaxTimeout))
+		})
+	})
+})
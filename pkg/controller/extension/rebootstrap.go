@@ -0,0 +1,82 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// handleRebootstrap restarts the bootstrap of Flux if it is requested by the operation annotation on the Extension or
+// Shoot. The bootstrapped condition and the bootstrap progress are removed in a single status patch, so that the
+// following bootstrap applies the Flux install manifest, source, and Kustomization again. Afterwards, the annotation is
+// removed from the Extension. As the extension cannot modify the Shoot, a rebootstrap requested on the Shoot is recorded
+// in the providerStatus until the user removes the annotation, so that it is only performed once.
+func (a *actuator) handleRebootstrap(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *EventRecorder,
+	ext *extensionsv1alpha1.Extension,
+	shoot *gardencorev1beta1.Shoot,
+) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+
+	extRequested := ext.Annotations[fluxv1alpha1.AnnotationOperation] == fluxv1alpha1.OperationRebootstrap
+	shootAnnotated := shoot.Annotations[fluxv1alpha1.AnnotationOperation] == fluxv1alpha1.OperationRebootstrap
+	shootRequested := shootAnnotated && !status.ShootRebootstrapStarted
+
+	if !extRequested && !shootRequested {
+		if !shootAnnotated && status.ShootRebootstrapStarted {
+			// the annotation has been removed from the Shoot, allow the next rebootstrap
+			status.ShootRebootstrapStarted = false
+			if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+				return fmt.Errorf("error resetting rebootstrap request in Extension status: %w", err)
+			}
+		}
+		return nil
+	}
+
+	log.Info("Restarting the bootstrap of Flux as requested", "extensionAnnotation", extRequested, "shootAnnotation", shootRequested)
+	recorder.Normal(EventReasonRebootstrapRequested, eventActionBootstrap, "Restarting the bootstrap of Flux as requested by the %s annotation", fluxv1alpha1.AnnotationOperation)
+
+	status.Bootstrap = nil
+	status.ShootRebootstrapStarted = shootAnnotated
+	raw, err := encodeProviderStatus(status)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(ext.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	ext.Status.Conditions = v1beta1helper.RemoveConditions(ext.Status.Conditions, fluxv1alpha1.ConditionBootstrapped)
+	ext.Status.ProviderStatus = raw
+	if err := a.client.Status().Patch(ctx, ext, patch); err != nil {
+		return fmt.Errorf("error removing %s condition from Extension status: %w", fluxv1alpha1.ConditionBootstrapped, err)
+	}
+
+	if extRequested {
+		patch := client.MergeFrom(ext.DeepCopy())
+		delete(ext.Annotations, fluxv1alpha1.AnnotationOperation)
+		if err := a.client.Patch(ctx, ext, patch); err != nil {
+			return fmt.Errorf("error removing %s annotation from Extension: %w", fluxv1alpha1.AnnotationOperation, err)
+		}
+	}
+
+	return nil
+}
+
+// HasRebootstrapAnnotation is a predicate for Extensions annotated with the rebootstrap operation.
+func HasRebootstrapAnnotation() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetAnnotations()[fluxv1alpha1.AnnotationOperation] == fluxv1alpha1.OperationRebootstrap
+	})
+}
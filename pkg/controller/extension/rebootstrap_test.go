@@ -0,0 +1,110 @@
+package extension
+
+import (
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("handleRebootstrap", func() {
+	var (
+		seedClient   client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+		a            *actuator
+
+		ext   *extensionsv1alpha1.Extension
+		shoot *gardencorev1beta1.Shoot
+	)
+
+	BeforeEach(func() {
+		seedClient = newSeedClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		a = NewActuator(seedClient, fakeRecorder, "garden-id", DefaultBootstrapOptions(), nil, nil, nil, nil).(*actuator)
+
+		ext = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shoot-flux",
+				Namespace: "shoot--foo--bar",
+			},
+		}
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		recorder = NewEventRecorder(fakeRecorder, ext)
+		shoot = &gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Name: "bar"}}
+
+		Expect(a.updateProviderStatus(ctx, ext, &fluxv1alpha1.FluxStatus{
+			Bootstrap: &fluxv1alpha1.BootstrapStatus{Phase: fluxv1alpha1.BootstrapPhaseDone, LastTransitionTime: metav1.Now()},
+		})).To(Succeed())
+		Expect(SetFluxBootstrapped(ctx, seedClient, ext)).To(Succeed())
+	})
+
+	providerStatus := func() *fluxv1alpha1.FluxStatus {
+		GinkgoHelper()
+		Expect(seedClient.Get(ctx, client.ObjectKeyFromObject(ext), ext)).To(Succeed())
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	It("should do nothing without the annotation", func() {
+		Expect(a.handleRebootstrap(ctx, log, recorder, ext, shoot)).To(Succeed())
+
+		Expect(providerStatus().Bootstrap.Phase).To(Equal(fluxv1alpha1.BootstrapPhaseDone))
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+		Expect(fakeRecorder.Events).NotTo(Receive())
+	})
+
+	It("should reset the bootstrap and remove the annotation from the Extension", func() {
+		metav1.SetMetaDataAnnotation(&ext.ObjectMeta, fluxv1alpha1.AnnotationOperation, fluxv1alpha1.OperationRebootstrap)
+		metav1.SetMetaDataAnnotation(&ext.ObjectMeta, "foo", "bar")
+		Expect(seedClient.Update(ctx, ext)).To(Succeed())
+
+		Expect(a.handleRebootstrap(ctx, log, recorder, ext, shoot)).To(Succeed())
+
+		status := providerStatus()
+		Expect(status.Bootstrap).To(BeNil())
+		Expect(status.ShootRebootstrapStarted).To(BeFalse())
+		Expect(IsFluxBootstrapped(ext)).To(BeFalse())
+		Expect(ext.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+		Expect(fakeRecorder.Events).To(Receive(Equal("Normal RebootstrapRequested Restarting the bootstrap of Flux as requested by the flux.extensions.gardener.cloud/operation annotation")))
+	})
+
+	It("should reset the bootstrap only once per annotation on the Shoot", func() {
+		metav1.SetMetaDataAnnotation(&shoot.ObjectMeta, fluxv1alpha1.AnnotationOperation, fluxv1alpha1.OperationRebootstrap)
+
+		By("resetting the bootstrap")
+		Expect(a.handleRebootstrap(ctx, log, recorder, ext, shoot)).To(Succeed())
+		status := providerStatus()
+		Expect(status.Bootstrap).To(BeNil())
+		Expect(status.ShootRebootstrapStarted).To(BeTrue())
+		Expect(IsFluxBootstrapped(ext)).To(BeFalse())
+
+		By("not resetting the completed bootstrap again while the annotation is present")
+		Expect(SetFluxBootstrapped(ctx, seedClient, ext)).To(Succeed())
+		Expect(a.handleRebootstrap(ctx, log, recorder, ext, shoot)).To(Succeed())
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+
+		By("allowing the next rebootstrap once the annotation is removed")
+		delete(shoot.Annotations, fluxv1alpha1.AnnotationOperation)
+		Expect(a.handleRebootstrap(ctx, log, recorder, ext, shoot)).To(Succeed())
+		Expect(providerStatus().ShootRebootstrapStarted).To(BeFalse())
+		Expect(IsFluxBootstrapped(ext)).To(BeTrue())
+	})
+})
+
+var _ = Describe("HasRebootstrapAnnotation", func() {
+	It("should only match Extensions with the rebootstrap annotation", func() {
+		ext := &extensionsv1alpha1.Extension{}
+		Expect(HasRebootstrapAnnotation().Generic(event.GenericEvent{Object: ext})).To(BeFalse())
+
+		metav1.SetMetaDataAnnotation(&ext.ObjectMeta, fluxv1alpha1.AnnotationOperation, fluxv1alpha1.OperationRebootstrap)
+		Expect(HasRebootstrapAnnotation().Generic(event.GenericEvent{Object: ext})).To(BeTrue())
+	})
+})
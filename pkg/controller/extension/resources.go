@@ -0,0 +1,110 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// additionalResourceTargetNamespace returns the namespace in the shoot the given resource is synced to.
+func additionalResourceTargetNamespace(additionalResource fluxv1alpha1.AdditionalResource, defaultNamespace string) string {
+	if namespace := ptr.Deref(additionalResource.TargetNamespace, ""); namespace != "" {
+		return namespace
+	}
+	return defaultNamespace
+}
+
+// additionalResourceLabels returns the labels of the resource in the shoot. If copyLabels is true, the labels of the
+// referenced resource are copied. Labels specified on the additional resource take precedence over copied labels. The
+// managed-by label is always set.
+func additionalResourceLabels(additionalResource fluxv1alpha1.AdditionalResource, referencedLabels map[string]string, copyLabels bool) map[string]string {
+	labels := map[string]string{}
+	if copyLabels {
+		maps.Copy(labels, referencedLabels)
+	}
+	maps.Copy(labels, additionalResource.Labels)
+	labels[managedByLabelKey] = managedByLabelValue
+	return labels
+}
+
+// additionalResourceAnnotations returns the annotations of the resource in the shoot: the existing annotations merged
+// with the annotations specified on the additional resource. Annotations added by others are kept. Annotations applied
+// in a previous reconciliation that are no longer specified are removed, they are tracked in the
+// managedAnnotationsAnnotation.
+func additionalResourceAnnotations(additionalResource fluxv1alpha1.AdditionalResource, existing map[string]string) map[string]string {
+	annotations := maps.Clone(existing)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if previous := annotations[managedAnnotationsAnnotation]; previous != "" {
+		for _, key := range strings.Split(previous, ",") {
+			if _, ok := additionalResource.Annotations[key]; !ok {
+				delete(annotations, key)
+			}
+		}
+	}
+	delete(annotations, managedAnnotationsAnnotation)
+
+	maps.Copy(annotations, additionalResource.Annotations)
+	if len(additionalResource.Annotations) > 0 {
+		annotations[managedAnnotationsAnnotation] = strings.Join(slices.Sorted(maps.Keys(additionalResource.Annotations)), ",")
+	}
+
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// ensureNamespace creates the given namespace in the shoot if it does not exist yet.
+func ensureNamespace(ctx context.Context, shootClient client.Client, name string) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := shootClient.Create(ctx, namespace); client.IgnoreAlreadyExists(err) != nil {
+		return fmt.Errorf("error creating %s namespace: %w", name, err)
+	}
+	return nil
+}
+
+// mapDataKeys returns a copy of data with the keys renamed according to keys. If keys is empty, all data is copied
+// unchanged. Otherwise, only the keys listed in keys are copied.
+func mapDataKeys[V any](data map[string]V, keys map[string]string) map[string]V {
+	if len(keys) == 0 {
+		return maps.Clone(data)
+	}
+
+	mapped := make(map[string]V, len(keys))
+	for key, targetKey := range keys {
+		value, ok := data[key]
+		if !ok {
+			continue
+		}
+		if targetKey == "" {
+			targetKey = key
+		}
+		mapped[targetKey] = value
+	}
+	return mapped
+}
+
+// missingDataKeys returns the keys listed in keys that are not present in the given set of data keys.
+func missingDataKeys(keys map[string]string, present sets.Set[string]) []string {
+	var missing []string
+	for key := range keys {
+		if !present.Has(key) {
+			missing = append(missing, key)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
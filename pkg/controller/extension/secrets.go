@@ -3,7 +3,6 @@ package extension
 import (
 	"context"
 	"fmt"
-	"maps"
 	"strconv"
 
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
@@ -22,12 +21,14 @@ import (
 )
 
 // ReconcileSecrets copies all secrets referenced in the extension (additionalSecretResources or
-// source.SecretResourceName), and deletes all secrets that are no longer referenced.
+// source.SecretResourceName), and deletes all secrets that are no longer referenced in any namespace. The shoot-info
+// Secret is managed by the extension as well, but is never pruned.
 // We cannot use gardener resource manager here, because we want to work in the namespace
 // "flux-system", which the resource manager is not configured for.
 func ReconcileSecrets(
 	ctx context.Context,
 	log logr.Logger,
+	recorder *EventRecorder,
 	seedClient client.Client,
 	shootClient client.Client,
 	seedNamespace string,
@@ -35,7 +36,7 @@ func ReconcileSecrets(
 	resources []gardencorev1beta1.NamedResourceReference,
 ) error {
 	shootNamespace := *config.Flux.Namespace
-	secretsToKeep := sets.Set[string]{}
+	secretsToKeep := sets.Set[client.ObjectKey]{}
 
 	secretResources := config.AdditionalSecretResources
 	if config.Source != nil && config.Source.SecretResourceName != nil {
@@ -61,29 +62,31 @@ func ReconcileSecrets(
 		}
 	}
 	for _, resource := range secretResources {
-		name, err := copySecretToShoot(ctx, log, seedClient, shootClient, seedNamespace, shootNamespace, resources, resource)
+		key, err := copySecretToShoot(ctx, log, recorder, seedClient, shootClient, seedNamespace, shootNamespace, resources, resource)
 		if err != nil {
 			return fmt.Errorf("failed to copy secret: %w", err)
 		}
-		secretsToKeep.Insert(name)
+		secretsToKeep.Insert(key)
 	}
 
 	// cleanup unreferenced secrets
 	secretList := &corev1.SecretList{}
 	if err := shootClient.List(ctx, secretList,
-		client.InNamespace(shootNamespace),
 		client.MatchingLabels{managedByLabelKey: managedByLabelValue},
 	); err != nil {
 		return fmt.Errorf("failed to list managed secrets in shoot: %w", err)
 	}
+	shootInfoSecretKey := client.ObjectKey{Namespace: shootNamespace, Name: shootInfoSecretName}
 	for _, secret := range secretList.Items {
-		if secretsToKeep.Has(secret.Name) {
+		if key := client.ObjectKeyFromObject(&secret); key == shootInfoSecretKey || secretsToKeep.Has(key) {
 			continue
 		}
 		if err := shootClient.Delete(ctx, &secret); client.IgnoreNotFound(err) != nil {
 			return fmt.Errorf("failed to delete secret that is no longer referenced: %w", err)
 		}
-		log.Info("Deleted secret that is no longer referenced by the extension", "secretName", secret.Name)
+		log.Info("Deleted secret that is no longer referenced by the extension", "secret", client.ObjectKeyFromObject(&secret))
+		recordSecretSync(shootClient, secretSyncOperationDeleted)
+		recorder.Normal(EventReasonSecretDeleted, eventActionDelete, "Deleted Secret %s that is no longer referenced", client.ObjectKeyFromObject(&secret))
 	}
 	return nil
 }
@@ -91,16 +94,17 @@ func ReconcileSecrets(
 func copySecretToShoot(
 	ctx context.Context,
 	log logr.Logger,
+	recorder *EventRecorder,
 	seedClient client.Client,
 	shootClient client.Client,
 	seedNamespace string,
-	targetNamespace string,
+	defaultNamespace string,
 	resources []gardencorev1beta1.NamedResourceReference,
 	additionalResource fluxv1alpha1.AdditionalResource,
-) (string, error) {
+) (client.ObjectKey, error) {
 	resource := v1beta1helper.GetResourceByName(resources, additionalResource.Name)
 	if resource == nil {
-		return "", fmt.Errorf("secret resource name does not match any of the resource names in Shoot.spec.resources[].name")
+		return client.ObjectKey{}, fmt.Errorf("secret resource name does not match any of the resource names in Shoot.spec.resources[].name")
 	}
 
 	seedSecret := &corev1.Secret{
@@ -110,7 +114,10 @@ func copySecretToShoot(
 		},
 	}
 	if err := seedClient.Get(ctx, client.ObjectKeyFromObject(seedSecret), seedSecret); err != nil {
-		return "", fmt.Errorf("error reading referenced secret: %w", err)
+		return client.ObjectKey{}, fmt.Errorf("error reading referenced secret: %w", err)
+	}
+	if missing := missingDataKeys(additionalResource.Keys, sets.KeySet(seedSecret.Data)); len(missing) > 0 {
+		return client.ObjectKey{}, fmt.Errorf("referenced secret %q does not contain keys %v", resource.ResourceRef.Name, missing)
 	}
 
 	name := resource.ResourceRef.Name
@@ -120,30 +127,33 @@ func copySecretToShoot(
 	shootSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: targetNamespace,
+			Namespace: additionalResourceTargetNamespace(additionalResource, defaultNamespace),
 		},
 	}
 
+	if shootSecret.Namespace != defaultNamespace {
+		if err := ensureNamespace(ctx, shootClient, shootSecret.Namespace); err != nil {
+			return client.ObjectKey{}, err
+		}
+	}
+
 	result, err := controllerutil.CreateOrUpdate(ctx, shootClient, shootSecret, func() error {
-		shootSecret.Data = maps.Clone(seedSecret.Data)
+		shootSecret.Data = mapDataKeys(seedSecret.Data, additionalResource.Keys)
 		shootSecret.Type = seedSecret.Type
-		labels := map[string]string{
-			managedByLabelKey: managedByLabelValue,
-		}
-		if shouldCopy, _ := strconv.ParseBool(seedSecret.Annotations["gardener-extension-shoot-flux/copy-labels"]); shouldCopy {
-			if seedSecret.Labels != nil {
-				for k, v := range seedSecret.Labels {
-					labels[k] = v
-				}
-			}
-		}
-		shootSecret.Labels = labels
+		copyLabels, _ := strconv.ParseBool(seedSecret.Annotations[copyLabelsAnnotation])
+		copyLabels = copyLabels || ptr.Deref(additionalResource.CopyLabels, false)
+		shootSecret.Labels = additionalResourceLabels(additionalResource, seedSecret.Labels, copyLabels)
+		shootSecret.Annotations = additionalResourceAnnotations(additionalResource, shootSecret.Annotations)
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return client.ObjectKey{}, err
+	}
+	log.Info("Synced secret", "secret", client.ObjectKeyFromObject(shootSecret), "result", result)
+	if result != controllerutil.OperationResultNone {
+		recordSecretSync(shootClient, string(result))
+		recorder.Normal(EventReasonSecretSynced, eventActionSync, "Synced Secret %s (%s)", client.ObjectKeyFromObject(shootSecret), result)
 	}
-	log.Info("Synced secret", "secretName", shootSecret.Name, "result", result)
 
-	return shootSecret.Name, nil
+	return client.ObjectKeyFromObject(shootSecret), nil
 }
@@ -4,12 +4,16 @@ import (
 	fluxmeta "github.com/fluxcd/pkg/apis/meta"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	. "github.com/gardener/gardener/pkg/utils/test/matchers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -18,8 +22,10 @@ import (
 
 var _ = Describe("ReconcileSecrets", Ordered, func() {
 	var (
-		shootClient client.Client
-		seedClient  client.Client
+		shootClient  client.Client
+		seedClient   client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
 
 		config    *fluxv1alpha1.FluxConfig
 		resources []gardencorev1beta1.NamedResourceReference
@@ -28,6 +34,8 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 	BeforeAll(func() {
 		shootClient = newShootClient()
 		seedClient = newSeedClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		recorder = NewEventRecorder(fakeRecorder, &extensionsv1alpha1.Extension{})
 		gitRepo := &sourcev1.GitRepository{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: sourcev1.GroupVersion.String(),
@@ -98,7 +106,7 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 
 	It("should create a referenced Source Secret", func() {
 		Expect(
-			ReconcileSecrets(ctx, log, seedClient, shootClient, extNS, config, resources),
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
 		).To(Succeed())
 
 		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
@@ -113,7 +121,7 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 
 	It("should create the additional secrets", func() {
 		Expect(
-			ReconcileSecrets(ctx, log, seedClient, shootClient, extNS, config, resources),
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
 		).To(Succeed())
 
 		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
@@ -125,6 +133,75 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 		Expect(createdSecret.Labels).NotTo(HaveKeyWithValue("app.kubernetes.io/test", "true"))
 	})
 
+	It("should set the declared labels and annotations", func() {
+		config.AdditionalSecretResources[0].CopyLabels = ptr.To(true)
+		config.AdditionalSecretResources[0].Labels = map[string]string{
+			"reconcile.fluxcd.io/watch": "Enabled",
+			managedByLabelKey:           "someone-else",
+		}
+		config.AdditionalSecretResources[0].Annotations = map[string]string{
+			"example.com/owner": "team-a",
+		}
+		DeferCleanup(func() {
+			config.AdditionalSecretResources[0].CopyLabels = nil
+			config.AdditionalSecretResources[0].Labels = nil
+			config.AdditionalSecretResources[0].Annotations = nil
+		})
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "extra",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdSecret), createdSecret)).To(Succeed())
+		Expect(createdSecret.Labels).To(Equal(map[string]string{
+			"app.kubernetes.io/test":    "true",
+			"reconcile.fluxcd.io/watch": "Enabled",
+			managedByLabelKey:           managedByLabelValue,
+		}))
+		Expect(createdSecret.Annotations).To(Equal(map[string]string{
+			"example.com/owner":          "team-a",
+			managedAnnotationsAnnotation: "example.com/owner",
+		}))
+	})
+
+	It("should keep annotations added by others and remove annotations that are no longer declared", func() {
+		config.AdditionalSecretResources[0].Annotations = map[string]string{
+			"example.com/owner": "team-a",
+			"example.com/tier":  "backend",
+		}
+		DeferCleanup(func() {
+			config.AdditionalSecretResources[0].Annotations = nil
+		})
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "extra",
+			Namespace: "flux-system",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdSecret), createdSecret)).To(Succeed())
+		createdSecret.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = "{}"
+		Expect(shootClient.Update(ctx, createdSecret)).To(Succeed())
+
+		delete(config.AdditionalSecretResources[0].Annotations, "example.com/tier")
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdSecret), createdSecret)).To(Succeed())
+		Expect(createdSecret.Annotations).To(Equal(map[string]string{
+			"example.com/owner": "team-a",
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			managedAnnotationsAnnotation:                       "example.com/owner",
+		}))
+	})
+
 	It("should change an existing secret", func() {
 		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
 			Name:      "extra",
@@ -135,7 +212,7 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 		Expect(shootClient.Update(ctx, createdSecret)).To(Succeed())
 
 		Expect(
-			ReconcileSecrets(ctx, log, seedClient, shootClient, extNS, config, resources),
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
 		).To(Succeed())
 
 		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdSecret), createdSecret)).To(Succeed())
@@ -166,7 +243,7 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 		})
 
 		Expect(
-			ReconcileSecrets(ctx, log, seedClient, shootClient, extNS, config, resources),
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
 		).To(Succeed())
 
 		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
@@ -179,9 +256,12 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 	})
 
 	It("should respect the target name and clean up the old secret", func() {
+		createdBefore := testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues("created"))
+		deletedBefore := testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues(secretSyncOperationDeleted))
+
 		config.AdditionalSecretResources[0].TargetName = ptr.To("surprise")
 		Expect(
-			ReconcileSecrets(ctx, log, seedClient, shootClient, extNS, config, resources),
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
 		).To(Succeed())
 
 		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
@@ -196,5 +276,134 @@ var _ = Describe("ReconcileSecrets", Ordered, func() {
 			Namespace: "flux-system",
 		}}
 		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(deletedSecret), deletedSecret)).To(BeNotFoundError())
+
+		Eventually(fakeRecorder.Events).Should(Receive(Equal("Normal SecretSynced Synced Secret flux-system/surprise (created)")))
+		Eventually(fakeRecorder.Events).Should(Receive(Equal("Normal SecretDeleted Deleted Secret flux-system/extra that is no longer referenced")))
+		Expect(testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues("created"))).To(Equal(createdBefore + 1))
+		Expect(testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues(secretSyncOperationDeleted))).To(Equal(deletedBefore + 1))
+	})
+
+	It("should not count the operations in dry-run mode", func() {
+		createdBefore := testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues("created"))
+		deletedBefore := testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues(secretSyncOperationDeleted))
+
+		targetName := config.AdditionalSecretResources[0].TargetName
+		DeferCleanup(func() { config.AdditionalSecretResources[0].TargetName = targetName })
+		config.AdditionalSecretResources[0].TargetName = ptr.To("dry-run")
+		c := NewDryRunClient(shootClient)
+		Expect(
+			ReconcileSecrets(ctx, log, nil, seedClient, c, extNS, config, resources),
+		).To(Succeed())
+		Expect(c.Changes()).To(ContainElements(
+			MatchFields(IgnoreExtras, Fields{"Action": Equal(fluxv1alpha1.ChangeActionCreate), "Name": Equal("dry-run")}),
+			MatchFields(IgnoreExtras, Fields{"Action": Equal(fluxv1alpha1.ChangeActionDelete), "Name": Equal("surprise")}),
+		))
+
+		Expect(testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues("created"))).To(Equal(createdBefore))
+		Expect(testutil.ToFloat64(secretSyncOperationsTotal.WithLabelValues(secretSyncOperationDeleted))).To(Equal(deletedBefore))
+	})
+
+	It("should sync the secret to the target namespace and map its keys", func() {
+		Expect(seedClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ref-credentials",
+				Namespace: extNS,
+			},
+			Data: map[string][]byte{
+				"user":  []byte("admin"),
+				"pass":  []byte("secret"),
+				"token": []byte("not-copied"),
+			},
+		})).To(Succeed())
+
+		resources = append(resources, gardencorev1beta1.NamedResourceReference{
+			Name: "credentials-secret",
+			ResourceRef: autoscalingv1.CrossVersionObjectReference{
+				Name: "credentials",
+				Kind: "Secret",
+			},
+		})
+		config.AdditionalSecretResources = append(config.AdditionalSecretResources, fluxv1alpha1.AdditionalResource{
+			Name:            "credentials-secret",
+			TargetNamespace: ptr.To("app"),
+			Keys: map[string]string{
+				"user": "username",
+				"pass": "password",
+			},
+		})
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+
+		createdSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "credentials",
+			Namespace: "app",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(createdSecret), createdSecret)).To(Succeed())
+		Expect(createdSecret.Data).To(Equal(map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		}))
+	})
+
+	It("should fail if a mapped key does not exist", func() {
+		config.AdditionalSecretResources[len(config.AdditionalSecretResources)-1].Keys["missing"] = ""
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(MatchError(ContainSubstring("does not contain keys [missing]")))
+
+		delete(config.AdditionalSecretResources[len(config.AdditionalSecretResources)-1].Keys, "missing")
+	})
+
+	It("should not delete the shoot-info Secret", func() {
+		shootInfo := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      shootInfoSecretName,
+			Namespace: "flux-system",
+			Labels: map[string]string{
+				managedByLabelKey: managedByLabelValue,
+			},
+		}}
+		Expect(shootClient.Create(ctx, shootInfo)).To(Succeed())
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(shootInfo), shootInfo)).To(Succeed())
+	})
+
+	It("should delete unreferenced secrets named shoot-info in other namespaces", func() {
+		unreferenced := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      shootInfoSecretName,
+			Namespace: "app",
+			Labels: map[string]string{
+				managedByLabelKey: managedByLabelValue,
+			},
+		}}
+		Expect(shootClient.Create(ctx, unreferenced)).To(Succeed())
+
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(unreferenced), unreferenced)).To(BeNotFoundError())
+	})
+
+	It("should clean up secrets in other namespaces", func() {
+		config.AdditionalSecretResources = config.AdditionalSecretResources[:len(config.AdditionalSecretResources)-1]
+		Expect(
+			ReconcileSecrets(ctx, log, recorder, seedClient, shootClient, extNS, config, resources),
+		).To(Succeed())
+
+		deletedSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "credentials",
+			Namespace: "app",
+		}}
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(deletedSecret), deletedSecret)).To(BeNotFoundError())
 	})
 })
@@ -0,0 +1,134 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// reconcileSuspension suspends or resumes the bootstrapped objects if FluxConfig.suspend changed compared to the
+// suspension recorded in the providerStatus. Otherwise, the live objects are left alone, so that objects suspended or
+// resumed by hand in the shoot (e.g., with "flux suspend") keep their state.
+func (a *actuator) reconcileSuspension(ctx context.Context, log logr.Logger, recorder *EventRecorder, shootClient client.Client, ext *extensionsv1alpha1.Extension, config *fluxv1alpha1.FluxConfig) error {
+	status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+	if err != nil {
+		return fmt.Errorf("error decoding providerStatus: %w", err)
+	}
+
+	suspend := ptr.Deref(config.Suspend, false)
+	if status.Suspended == suspend {
+		return nil
+	}
+
+	if err := ReconcileSuspension(ctx, log, recorder, shootClient, config); err != nil {
+		return err
+	}
+
+	status.Suspended = suspend
+	if err := a.updateProviderStatus(ctx, ext, status); err != nil {
+		return fmt.Errorf("error recording suspension in Extension status: %w", err)
+	}
+	return nil
+}
+
+// ReconcileSuspension suspends or resumes the reconciliation of the bootstrapped source and Kustomization in the shoot
+// according to FluxConfig.suspend by setting their spec.suspend field. Objects suspended in their templates stay
+// suspended. Like "flux resume", resumed objects are annotated to be reconciled immediately. Objects that don't exist
+// in the shoot are skipped. The live objects are read as unstructured objects, so c doesn't need the Flux types in its
+// scheme.
+func ReconcileSuspension(ctx context.Context, log logr.Logger, recorder *EventRecorder, c client.Client, config *fluxv1alpha1.FluxConfig) error {
+	objects, err := bootstrapObjects(config)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object.template)
+		if err != nil {
+			return err
+		}
+		templateSuspend, _, _ := unstructured.NestedBool(content, "spec", "suspend")
+
+		if err := setSuspend(ctx, log, recorder, c, object, ptr.Deref(config.Suspend, false) || templateSuspend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setSuspend sets spec.suspend of the live object to the given value if it differs.
+func setSuspend(ctx context.Context, log logr.Logger, recorder *EventRecorder, c client.Client, object bootstrapObject, suspend bool) error {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(object.gvk)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(object.template), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading %s: %w", object, err)
+	}
+
+	if current, _, _ := unstructured.NestedBool(live.Object, "spec", "suspend"); current == suspend {
+		return nil
+	}
+
+	patch := client.MergeFrom(live.DeepCopy())
+	if err := unstructured.SetNestedField(live.Object, suspend, "spec", "suspend"); err != nil {
+		return err
+	}
+	if !suspend {
+		setReconcileRequest(live)
+	}
+	if err := c.Patch(ctx, live, patch); err != nil {
+		return fmt.Errorf("error setting spec.suspend=%t on %s: %w", suspend, object, err)
+	}
+
+	if suspend {
+		log.Info("Suspended Flux object", "object", object.String())
+		recorder.Normal(EventReasonSuspended, eventActionSuspend, "Suspended Flux %s", object)
+	} else {
+		log.Info("Resumed Flux object", "object", object.String())
+		recorder.Normal(EventReasonResumed, eventActionResume, "Resumed Flux %s", object)
+	}
+	return nil
+}
+
+// requestReconciliation annotates the live object to be reconciled by Flux immediately, like "flux reconcile". It
+// returns false if the object doesn't exist in the shoot.
+func requestReconciliation(ctx context.Context, c client.Client, object bootstrapObject) (bool, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(object.gvk)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(object.template), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s: %w", object, err)
+	}
+
+	patch := client.MergeFrom(live.DeepCopy())
+	setReconcileRequest(live)
+	if err := c.Patch(ctx, live, patch); err != nil {
+		return false, fmt.Errorf("error requesting reconciliation of %s: %w", object, err)
+	}
+	return true, nil
+}
+
+// setReconcileRequest sets the annotation requesting Flux to reconcile the given object.
+func setReconcileRequest(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[fluxmeta.ReconcileRequestAnnotation] = time.Now().Format(time.RFC3339Nano)
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,274 @@
+package extension
+
+import (
+	"context"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("ReconcileSuspension", func() {
+	var (
+		shootClient  client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+
+		config        *fluxv1alpha1.FluxConfig
+		gitRepo       *sourcev1.GitRepository
+		kustomization *kustomizev1.Kustomization
+	)
+
+	BeforeEach(func() {
+		shootClient = newShootClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		recorder = NewEventRecorder(fakeRecorder, &extensionsv1alpha1.Extension{ObjectMeta: metav1.ObjectMeta{Name: "shoot-flux"}})
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: sourcev1.GitRepositorySpec{
+				URL:      "http://example.com",
+				Interval: metav1.Duration{Duration: time.Minute},
+			},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flux-system",
+				Namespace: "flux-system",
+			},
+			Spec: kustomizev1.KustomizationSpec{
+				Path: "/some/path",
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{
+					Kind: sourcev1.GitRepositoryKind,
+					Name: "flux-system",
+				},
+			},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Source: &fluxv1alpha1.Source{
+				Template: encodeSourceObject(gitRepo.DeepCopy()),
+			},
+			Kustomization: &fluxv1alpha1.Kustomization{
+				Template: *kustomization.DeepCopy(),
+			},
+		}
+
+		Expect(BootstrapSource(ctx, log, nil, shootClient, config.Source, false)).To(Succeed())
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, false)).To(Succeed())
+	})
+
+	readObjects := func() {
+		GinkgoHelper()
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), gitRepo)).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(kustomization), kustomization)).To(Succeed())
+	}
+
+	It("should not change the objects if suspend is not set", func() {
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeFalse())
+		Expect(kustomization.Spec.Suspend).To(BeFalse())
+		Expect(kustomization.Annotations).NotTo(HaveKey(fluxmeta.ReconcileRequestAnnotation))
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should suspend and resume the objects", func() {
+		config.Suspend = ptr.To(true)
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeTrue())
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+		Expect(fakeRecorder.Events).To(HaveLen(2))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Suspended Flux GitRepository flux-system/flux-system"))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Suspended Flux Kustomization flux-system/flux-system"))
+
+		config.Suspend = ptr.To(false)
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeFalse())
+		Expect(kustomization.Spec.Suspend).To(BeFalse())
+		Expect(gitRepo.Annotations).To(HaveKey(fluxmeta.ReconcileRequestAnnotation))
+		Expect(kustomization.Annotations).To(HaveKey(fluxmeta.ReconcileRequestAnnotation))
+		Expect(fakeRecorder.Events).To(HaveLen(2))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Resumed Flux GitRepository flux-system/flux-system"))
+		Expect(<-fakeRecorder.Events).To(ContainSubstring("Resumed Flux Kustomization flux-system/flux-system"))
+	})
+
+	It("should keep objects suspended in their templates suspended", func() {
+		config.Kustomization.Template.Spec.Suspend = true
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeFalse())
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+	})
+
+	It("should never write suspended objects with suspend=false when bootstrapping them again", func() {
+		config.Suspend = ptr.To(true)
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		c := &unsuspendRecordingClient{Client: shootClient}
+		Expect(BootstrapSource(ctx, log, nil, c, config.Source, true)).To(Succeed())
+		Expect(BootstrapKustomization(ctx, log, nil, c, config.Kustomization, true)).To(Succeed())
+		Expect(ReconcileSuspension(ctx, log, recorder, c, config)).To(Succeed())
+		Expect(c.unsuspended).To(BeEmpty())
+
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeTrue())
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+	})
+
+	It("should skip objects that don't exist", func() {
+		Expect(shootClient.Delete(ctx, kustomization)).To(Succeed())
+		config.Suspend = ptr.To(true)
+		Expect(ReconcileSuspension(ctx, log, recorder, shootClient, config)).To(Succeed())
+
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), gitRepo)).To(Succeed())
+		Expect(gitRepo.Spec.Suspend).To(BeTrue())
+	})
+})
+
+var _ = Describe("reconcileSuspension", func() {
+	var (
+		seedClient   client.Client
+		shootClient  client.Client
+		fakeRecorder *events.FakeRecorder
+		recorder     *EventRecorder
+		a            *actuator
+
+		ext           *extensionsv1alpha1.Extension
+		config        *fluxv1alpha1.FluxConfig
+		gitRepo       *sourcev1.GitRepository
+		kustomization *kustomizev1.Kustomization
+	)
+
+	BeforeEach(func() {
+		seedClient = newSeedClient()
+		shootClient = newShootClient()
+		fakeRecorder = events.NewFakeRecorder(100)
+		a = NewActuator(seedClient, fakeRecorder, "garden-id", DefaultBootstrapOptions(), nil, nil, nil, nil).(*actuator)
+
+		ext = &extensionsv1alpha1.Extension{ObjectMeta: metav1.ObjectMeta{Name: "shoot-flux", Namespace: "shoot--foo--bar"}}
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		recorder = NewEventRecorder(fakeRecorder, ext)
+
+		gitRepo = &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec:       sourcev1.GitRepositorySpec{URL: "http://example.com"},
+		}
+		kustomization = &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec:       kustomizev1.KustomizationSpec{Path: "/some/path"},
+		}
+		config = &fluxv1alpha1.FluxConfig{
+			Source:        &fluxv1alpha1.Source{Template: encodeSourceObject(gitRepo.DeepCopy())},
+			Kustomization: &fluxv1alpha1.Kustomization{Template: *kustomization.DeepCopy()},
+		}
+
+		Expect(BootstrapSource(ctx, log, nil, shootClient, config.Source, false)).To(Succeed())
+		Expect(BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, false)).To(Succeed())
+	})
+
+	readObjects := func() {
+		GinkgoHelper()
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(gitRepo), gitRepo)).To(Succeed())
+		Expect(shootClient.Get(ctx, client.ObjectKeyFromObject(kustomization), kustomization)).To(Succeed())
+	}
+
+	suspended := func() bool {
+		GinkgoHelper()
+		Expect(seedClient.Get(ctx, client.ObjectKeyFromObject(ext), ext)).To(Succeed())
+		status, err := a.DecodeProviderStatus(ext.Status.ProviderStatus)
+		Expect(err).NotTo(HaveOccurred())
+		return status.Suspended
+	}
+
+	It("should keep objects suspended by hand suspended", func() {
+		readObjects()
+		kustomization.Spec.Suspend = true
+		Expect(shootClient.Update(ctx, kustomization)).To(Succeed())
+
+		Expect(a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		config.Suspend = ptr.To(false)
+		Expect(a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+
+		readObjects()
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+		Expect(kustomization.Annotations).NotTo(HaveKey(fluxmeta.ReconcileRequestAnnotation))
+		Expect(fakeRecorder.Events).To(BeEmpty())
+	})
+
+	It("should only suspend and resume the objects if the suspend flag changed", func() {
+		config.Suspend = ptr.To(true)
+		Expect(a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeTrue())
+		Expect(kustomization.Spec.Suspend).To(BeTrue())
+		Expect(suspended()).To(BeTrue())
+
+		By("not touching the objects while the flag is unchanged")
+		gitRepo.Spec.Suspend = false
+		Expect(shootClient.Update(ctx, gitRepo)).To(Succeed())
+		Expect(a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		readObjects()
+		Expect(gitRepo.Spec.Suspend).To(BeFalse())
+
+		By("resuming the objects once the flag is removed")
+		config.Suspend = nil
+		Expect(a.reconcileSuspension(ctx, log, recorder, shootClient, ext, config)).To(Succeed())
+		readObjects()
+		Expect(kustomization.Spec.Suspend).To(BeFalse())
+		Expect(kustomization.Annotations).To(HaveKey(fluxmeta.ReconcileRequestAnnotation))
+		Expect(suspended()).To(BeFalse())
+	})
+})
+
+// unsuspendRecordingClient records all writes of Flux objects with spec.suspend=false.
+type unsuspendRecordingClient struct {
+	client.Client
+	unsuspended []string
+}
+
+func (c *unsuspendRecordingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.record(obj)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *unsuspendRecordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.record(obj)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *unsuspendRecordingClient) record(obj client.Object) {
+	var suspend bool
+	switch obj := obj.(type) {
+	case *sourcev1.GitRepository:
+		suspend = obj.Spec.Suspend
+	case *kustomizev1.Kustomization:
+		suspend = obj.Spec.Suspend
+	case *unstructured.Unstructured:
+		suspend, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	default:
+		return
+	}
+	if !suspend {
+		c.unsuspended = append(c.unsuspended, client.ObjectKeyFromObject(obj).String())
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/extension"
+)
+
+// inSyncCheck checks whether the bootstrapped source and Kustomization in the shoot still match the templates in the
+// providerConfig. It only reports drift and never changes the objects.
+type inSyncCheck struct {
+	seedClient  client.Client
+	shootClient client.Client
+	logger      logr.Logger
+}
+
+var (
+	_ healthcheck.HealthCheck  = &inSyncCheck{}
+	_ healthcheck.SourceClient = &inSyncCheck{}
+	_ healthcheck.TargetClient = &inSyncCheck{}
+)
+
+// NewInSyncCheck returns a health check that reports drift between the bootstrapped Flux objects and the templates in
+// the providerConfig.
+func NewInSyncCheck() healthcheck.HealthCheck {
+	return &inSyncCheck{}
+}
+
+// InjectSourceClient injects the seed client.
+func (c *inSyncCheck) InjectSourceClient(seedClient client.Client) {
+	c.seedClient = seedClient
+}
+
+// InjectTargetClient injects the shoot client.
+func (c *inSyncCheck) InjectTargetClient(shootClient client.Client) {
+	c.shootClient = shootClient
+}
+
+// SetLoggerSuffix injects the logger.
+func (c *inSyncCheck) SetLoggerSuffix(provider, extension string) {
+	c.logger = logf.Log.WithName(fmt.Sprintf("%s-%s-healthcheck-flux-config-in-sync", provider, extension))
+}
+
+// Check compares the live objects with the templates. Before the initial bootstrap and in dry-run mode, there is nothing
+// to compare and the check succeeds.
+func (c *inSyncCheck) Check(ctx context.Context, request types.NamespacedName) (*healthcheck.SingleCheckResult, error) {
+	ext := &extensionsv1alpha1.Extension{}
+	if err := c.seedClient.Get(ctx, request, ext); err != nil {
+		return nil, fmt.Errorf("error reading Extension: %w", err)
+	}
+
+	if !extension.IsFluxBootstrapped(ext) {
+		return &healthcheck.SingleCheckResult{Status: gardencorev1beta1.ConditionTrue}, nil
+	}
+
+	decoder := serializer.NewCodecFactory(c.seedClient.Scheme()).UniversalDecoder()
+	config, err := extension.DecodeProviderConfig(c.seedClient.Scheme(), decoder, ext.Spec.ProviderConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding providerConfig: %w", err)
+	}
+	if ptr.Deref(config.DryRun, false) {
+		return &healthcheck.SingleCheckResult{Status: gardencorev1beta1.ConditionTrue}, nil
+	}
+
+	drift, err := extension.DetectDrift(ctx, c.shootClient, config)
+	if err != nil {
+		return nil, fmt.Errorf("error detecting drift of Flux objects: %w", err)
+	}
+	if len(drift) > 0 {
+		c.logger.Info("Flux objects differ from the providerConfig", "extension", request, "drift", drift)
+		return &healthcheck.SingleCheckResult{
+			Status: gardencorev1beta1.ConditionFalse,
+			Detail: "The Flux objects in the shoot differ from the providerConfig: " + strings.Join(drift, "; "),
+		}, nil
+	}
+
+	return &healthcheck.SingleCheckResult{Status: gardencorev1beta1.ConditionTrue}, nil
+}
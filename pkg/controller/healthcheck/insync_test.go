@@ -0,0 +1,135 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/gardener/gardener/extensions/pkg/controller/healthcheck"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("InSyncCheck", func() {
+	const providerConfig = `{
+  "apiVersion": "flux.extensions.gardener.cloud/v1alpha1",
+  "kind": "FluxConfig",
+  "source": {
+    "template": {
+      "apiVersion": "source.toolkit.fluxcd.io/v1",
+      "kind": "GitRepository",
+      "spec": {"url": "https://example.com/repo", "ref": {"branch": "main"}}
+    }
+  },
+  "kustomization": {"template": {"spec": {"path": "clusters/bar"}}}
+}`
+
+	var (
+		ctx         context.Context
+		seedClient  client.Client
+		shootClient client.Client
+		check       healthcheck.HealthCheck
+		ext         *extensionsv1alpha1.Extension
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		seedScheme := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(seedScheme)).To(Succeed())
+		Expect(fluxv1alpha1.AddToScheme(seedScheme)).To(Succeed())
+		seedClient = fake.NewClientBuilder().WithScheme(seedScheme).Build()
+
+		shootScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(shootScheme)).To(Succeed())
+		Expect(sourcev1.AddToScheme(shootScheme)).To(Succeed())
+		Expect(kustomizev1.AddToScheme(shootScheme)).To(Succeed())
+		shootClient = fake.NewClientBuilder().WithScheme(shootScheme).Build()
+
+		check = NewInSyncCheck()
+		check.(healthcheck.SourceClient).InjectSourceClient(seedClient)
+		check.(healthcheck.TargetClient).InjectTargetClient(shootClient)
+		check.SetLoggerSuffix("shoot-flux", "Extension")
+
+		ext = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: "shoot-flux", Namespace: "shoot--foo--bar"},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type:           fluxv1alpha1.ExtensionType,
+					ProviderConfig: &runtime.RawExtension{Raw: []byte(providerConfig)},
+				},
+			},
+			Status: extensionsv1alpha1.ExtensionStatus{
+				DefaultStatus: extensionsv1alpha1.DefaultStatus{
+					Conditions: []gardencorev1beta1.Condition{{
+						Type:   fluxv1alpha1.ConditionBootstrapped,
+						Status: gardencorev1beta1.ConditionTrue,
+					}},
+				},
+			},
+		}
+	})
+
+	createObjects := func(branch string) {
+		GinkgoHelper()
+		Expect(shootClient.Create(ctx, &sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec: sourcev1.GitRepositorySpec{
+				URL:       "https://example.com/repo",
+				Reference: &sourcev1.GitRepositoryRef{Branch: branch},
+				Interval:  metav1.Duration{Duration: time.Minute},
+			},
+		})).To(Succeed())
+		Expect(shootClient.Create(ctx, &kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec: kustomizev1.KustomizationSpec{
+				Path:     "clusters/bar",
+				Interval: metav1.Duration{Duration: time.Minute},
+				SourceRef: kustomizev1.CrossNamespaceSourceReference{
+					Kind:      sourcev1.GitRepositoryKind,
+					Name:      "flux-system",
+					Namespace: "flux-system",
+				},
+			},
+		})).To(Succeed())
+	}
+
+	It("should succeed if the objects match the providerConfig", func() {
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		createObjects("main")
+
+		Expect(check.Check(ctx, client.ObjectKeyFromObject(ext))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(gardencorev1beta1.ConditionTrue),
+		})))
+	})
+
+	It("should list the drifted fields", func() {
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+		createObjects("personal")
+
+		Expect(check.Check(ctx, client.ObjectKeyFromObject(ext))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(gardencorev1beta1.ConditionFalse),
+			"Detail": Equal("The Flux objects in the shoot differ from the providerConfig: GitRepository flux-system/flux-system: spec.ref.branch"),
+		})))
+	})
+
+	It("should succeed before the initial bootstrap", func() {
+		ext.Status.Conditions = nil
+		Expect(seedClient.Create(ctx, ext)).To(Succeed())
+
+		Expect(check.Check(ctx, client.ObjectKeyFromObject(ext))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(gardencorev1beta1.ConditionTrue),
+		})))
+	})
+})
@@ -17,6 +17,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
 )
 
 var (
@@ -28,15 +30,11 @@ var (
 )
 
 // RegisterHealthChecks registers health checks for the Extension resource.
-// The controller doesn't actually perform any health checks. However, it removes the health check Conditions written
-// by previous versions of the extension from the Extension status.
+// The health checks don't contribute to the Shoot's health, as the extension only bootstraps Flux once and the
+// reconciliation of the Flux resources is up to Flux itself. Instead, the FluxConfigInSync condition reports drift
+// between the bootstrapped objects and the providerConfig. The controller also removes the health check Conditions
+// written by previous versions of the extension from the Extension status.
 func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs) error {
-	// Health checks don't make any sense as long as the extension only cares about bootstrapping flux once.
-	// This happens during Extension reconciliation, so the shoot reconciliation either continues successfully or fails at
-	// this step.
-	// During the cluster's lifetime, the status cannot change as such, as the extension has already fulfilled its
-	// purpose.
-	// TODO: add real health checks when this extension offers reconciling the flux resources
 	return healthcheck.DefaultRegistration(
 		"shoot-flux",
 		extensionsv1alpha1.SchemeGroupVersion.WithKind(extensionsv1alpha1.ExtensionResource),
@@ -45,7 +43,12 @@ func RegisterHealthChecks(mgr manager.Manager, opts healthcheck.DefaultAddArgs)
 		mgr,
 		opts,
 		nil,
-		nil,
+		[]healthcheck.ConditionTypeToHealthCheck{
+			{
+				ConditionType: fluxv1alpha1.ConditionInSync,
+				HealthCheck:   NewInSyncCheck(),
+			},
+		},
 		sets.New(gardencorev1beta1.ShootSystemComponentsHealthy),
 	)
 }
@@ -0,0 +1,180 @@
+package fluxmanifests
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	fluxinstall "github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache caches generated Flux install manifests in memory and optionally on disk. Concurrent requests for the same key
+// are deduplicated, so that a manifest is generated only once even if many shoots are bootstrapped in parallel. The
+// memory cache is bounded by the total size of the cached manifests, the least recently used manifests are evicted
+// first. A nil Cache doesn't cache anything.
+type Cache struct {
+	maxBytes int64
+	dir      string
+
+	group singleflight.Group
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+type cacheEntry struct {
+	key      string
+	manifest []byte
+}
+
+// NewCache returns a Cache that keeps up to maxBytes of manifests in memory. If dir is not empty, generated manifests
+// are also persisted in dir and survive restarts.
+func NewCache(maxBytes int64, dir string) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		dir:      dir,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+// CacheKey returns the cache key for an install manifest generated with the given options from the given source.
+func CacheKey(options fluxinstall.Options, source *Source) (string, error) {
+	data, err := json.Marshal(struct {
+		Source  string
+		Options fluxinstall.Options
+	}{source.ID(), options})
+	if err != nil {
+		return "", fmt.Errorf("error computing cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the manifest cached for the given key. On a cache miss, the manifest is generated with the given function
+// and added to the cache. Errors are not cached. The returned manifest is shared and must not be modified.
+func (c *Cache) Get(key string, generate func() ([]byte, error)) ([]byte, error) {
+	if c == nil {
+		return generate()
+	}
+
+	if manifest, ok := c.getMemory(key); ok {
+		return manifest, nil
+	}
+
+	manifest, err, _ := c.group.Do(key, func() (any, error) {
+		if manifest, ok := c.getMemory(key); ok {
+			return manifest, nil
+		}
+
+		manifest, err := c.readDisk(key)
+		if err != nil {
+			return nil, err
+		}
+		if manifest == nil {
+			if manifest, err = generate(); err != nil {
+				return nil, err
+			}
+			if err := c.writeDisk(key, manifest); err != nil {
+				return nil, err
+			}
+		}
+
+		c.add(key, manifest)
+		return manifest, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest.([]byte), nil
+}
+
+func (c *Cache) getMemory(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*cacheEntry).manifest, true
+}
+
+func (c *Cache) add(key string, manifest []byte) {
+	size := int64(len(manifest))
+	if size > c.maxBytes {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, manifest: manifest})
+	c.size += size
+
+	for c.size > c.maxBytes {
+		oldest := c.lru.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.lru.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.manifest))
+	}
+}
+
+func (c *Cache) readDisk(key string) ([]byte, error) {
+	if c.dir == "" {
+		return nil, nil
+	}
+
+	manifest, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cached manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func (c *Cache) writeDisk(key string, manifest []byte) error {
+	if c.dir == "" {
+		return nil
+	}
+
+	// write to a temporary file first, so that other processes sharing the directory never read partial manifests
+	file, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error caching manifest: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(manifest); err != nil {
+		file.Close()
+		return fmt.Errorf("error caching manifest: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error caching manifest: %w", err)
+	}
+	if err := os.Rename(file.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("error caching manifest: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".yaml")
+}
@@ -0,0 +1,130 @@
+package fluxmanifests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing/fstest"
+
+	fluxinstall "github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		cache *Cache
+		calls atomic.Int32
+	)
+
+	BeforeEach(func() {
+		cache = NewCache(10, "")
+		calls.Store(0)
+	})
+
+	generate := func(manifest string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			calls.Add(1)
+			return []byte(manifest), nil
+		}
+	}
+
+	It("should generate the manifest only once", func() {
+		Expect(cache.Get("a", generate("aaa"))).To(BeEquivalentTo("aaa"))
+		Expect(cache.Get("a", generate("other"))).To(BeEquivalentTo("aaa"))
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should deduplicate concurrent requests", func() {
+		release := make(chan struct{})
+		blockingGenerate := func() ([]byte, error) {
+			calls.Add(1)
+			<-release
+			return []byte("aaa"), nil
+		}
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				defer GinkgoRecover()
+				Expect(cache.Get("a", blockingGenerate)).To(BeEquivalentTo("aaa"))
+			})
+		}
+
+		Eventually(calls.Load).Should(BeEquivalentTo(1))
+		close(release)
+		wg.Wait()
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should evict the least recently used manifests if the cache is full", func() {
+		Expect(cache.Get("a", generate("aaaa"))).To(BeEquivalentTo("aaaa"))
+		Expect(cache.Get("b", generate("bbbb"))).To(BeEquivalentTo("bbbb"))
+		Expect(cache.Get("a", generate("aaaa"))).To(BeEquivalentTo("aaaa"))
+		Expect(cache.Get("c", generate("cccc"))).To(BeEquivalentTo("cccc"))
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+
+		By("keeping the recently used manifest")
+		Expect(cache.Get("a", generate("aaaa"))).To(BeEquivalentTo("aaaa"))
+		Expect(calls.Load()).To(BeEquivalentTo(3))
+
+		By("regenerating the evicted manifest")
+		Expect(cache.Get("b", generate("bbbb"))).To(BeEquivalentTo("bbbb"))
+		Expect(calls.Load()).To(BeEquivalentTo(4))
+	})
+
+	It("should not cache manifests exceeding the maximum size", func() {
+		Expect(cache.Get("a", generate("aaaaaaaaaaa"))).To(BeEquivalentTo("aaaaaaaaaaa"))
+		Expect(cache.Get("a", generate("aaaaaaaaaaa"))).To(BeEquivalentTo("aaaaaaaaaaa"))
+		Expect(calls.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should not cache errors", func() {
+		_, err := cache.Get("a", func() ([]byte, error) { return nil, errors.New("fake") })
+		Expect(err).To(MatchError("fake"))
+		Expect(cache.Get("a", generate("aaa"))).To(BeEquivalentTo("aaa"))
+	})
+
+	It("should persist the manifests on disk", func() {
+		dir := GinkgoT().TempDir()
+		Expect(NewCache(10, dir).Get("a", generate("aaa"))).To(BeEquivalentTo("aaa"))
+		Expect(os.ReadFile(filepath.Join(dir, "a.yaml"))).To(BeEquivalentTo("aaa"))
+
+		Expect(NewCache(10, dir).Get("a", generate("other"))).To(BeEquivalentTo("aaa"))
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should not cache anything if nil", func() {
+		var nilCache *Cache
+		Expect(nilCache.Get("a", generate("aaa"))).To(BeEquivalentTo("aaa"))
+		Expect(nilCache.Get("a", generate("aaa"))).To(BeEquivalentTo("aaa"))
+		Expect(calls.Load()).To(BeEquivalentTo(2))
+	})
+
+	Describe("#CacheKey", func() {
+		It("should depend on the install options", func() {
+			options := fluxinstall.MakeDefaultOptions()
+			key, err := CacheKey(options, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(CacheKey(options, nil)).To(Equal(key))
+
+			options.Registry = "registry.example.com"
+			Expect(CacheKey(options, nil)).NotTo(Equal(key))
+		})
+
+		It("should depend on the manifest source", func() {
+			options := fluxinstall.MakeDefaultOptions()
+			key, err := CacheKey(options, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(CacheKey(options, &Source{})).To(Equal(key))
+
+			dirKey, err := CacheKey(options, &Source{dir: "/manifests"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dirKey).NotTo(Equal(key))
+			Expect(CacheKey(options, &Source{dir: "/other"})).NotTo(Equal(dirKey))
+			Expect(CacheKey(options, &Source{embedded: fstest.MapFS{}})).NotTo(Equal(key))
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package fluxmanifests
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// defaultDigests is the digest catalog shipped with the extension, see `make update-image-digests`.
+//
+//go:embed digests.yaml
+var defaultDigests []byte
+
+// DigestCatalog maps the images of the Flux components to their digests. The keys have the form "<component>:<tag>",
+// e.g., "source-controller:v1.9.3", so that the catalog also applies to images mirrored to a different registry.
+type DigestCatalog map[string]string
+
+// DefaultDigestCatalog returns the digest catalog shipped with the extension.
+func DefaultDigestCatalog() (DigestCatalog, error) {
+	catalog := DigestCatalog{}
+	if err := yaml.Unmarshal(defaultDigests, &catalog); err != nil {
+		return nil, fmt.Errorf("error parsing default digest catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// LoadDigestCatalog returns the digest catalog shipped with the extension extended by the catalog in the given file.
+// Entries from the file take precedence. path may be empty.
+func LoadDigestCatalog(path string) (DigestCatalog, error) {
+	catalog, err := DefaultDigestCatalog()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return catalog, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading digest catalog: %w", err)
+	}
+	fileCatalog := DigestCatalog{}
+	if err := yaml.Unmarshal(data, &fileCatalog); err != nil {
+		return nil, fmt.Errorf("error parsing digest catalog %s: %w", path, err)
+	}
+	maps.Copy(catalog, fileCatalog)
+
+	return catalog, nil
+}
+
+// Digest returns the digest of the given component image.
+func (c DigestCatalog) Digest(component, tag string) (string, bool) {
+	digest, ok := c[component+":"+tag]
+	return digest, ok
+}
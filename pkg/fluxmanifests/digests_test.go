@@ -0,0 +1,35 @@
+package fluxmanifests
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestCatalog", func() {
+	It("should parse the default catalog", func() {
+		_, err := DefaultDigestCatalog()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should extend the default catalog with the given file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "digests.yaml")
+		Expect(os.WriteFile(path, []byte("source-controller:v1.9.3: sha256:abc\n"), 0o600)).To(Succeed())
+
+		catalog, err := LoadDigestCatalog(path)
+		Expect(err).NotTo(HaveOccurred())
+		digest, ok := catalog.Digest("source-controller", "v1.9.3")
+		Expect(ok).To(BeTrue())
+		Expect(digest).To(Equal("sha256:abc"))
+
+		_, ok = catalog.Digest("source-controller", "v0.0.0")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should fail if the file cannot be read", func() {
+		_, err := LoadDigestCatalog(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).To(MatchError(ContainSubstring("error reading digest catalog")))
+	})
+})
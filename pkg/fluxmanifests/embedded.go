@@ -0,0 +1,21 @@
+//go:build embed_flux_manifests
+
+package fluxmanifests
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// manifests contains the Flux install manifests downloaded by `make embed-flux-manifests`. They are only embedded if
+// the binary is built with the embed_flux_manifests build tag.
+//
+//go:embed all:manifests
+var manifests embed.FS
+
+func init() {
+	var err error
+	if embedded, err = fs.Sub(manifests, "manifests"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,13 @@
+package fluxmanifests
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFluxManifests(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flux Manifests Suite")
+}
@@ -0,0 +1,127 @@
+// Package fluxmanifests provides the Flux install manifests for air-gapped installations, where the manifests cannot be
+// downloaded from GitHub during reconciliation.
+package fluxmanifests
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrVersionNotAvailable is returned if the manifests for a requested Flux version are not available locally.
+var ErrVersionNotAvailable = errors.New("flux manifests are not available locally")
+
+// embedded contains the manifests embedded at build time, see embedded.go. It is nil if no manifests are embedded.
+var embedded fs.FS
+
+// Source provides the Flux install manifests from a local directory and the manifests embedded at build time. Both
+// contain one directory per Flux version with the extracted manifests.tar.gz of the corresponding Flux release, e.g.:
+//
+//	v2.9.2/
+//	  helm-controller.yaml
+//	  kustomize-controller.yaml
+//	  ...
+//
+// The local directory takes precedence over the embedded manifests.
+type Source struct {
+	dir      string
+	embedded fs.FS
+}
+
+// NewSource returns a Source for the given directory and the manifests embedded at build time. dir may be empty.
+func NewSource(dir string) *Source {
+	return &Source{
+		dir:      dir,
+		embedded: embedded,
+	}
+}
+
+// IsLocal returns true if local manifests are configured or embedded. Otherwise, the manifests need to be downloaded
+// from GitHub. A nil Source is not local.
+func (s *Source) IsLocal() bool {
+	return s != nil && (s.dir != "" || s.embedded != nil)
+}
+
+// ID identifies the source of the manifests: GitHub, or the local directory and the embedded manifests. Manifests
+// generated from different sources might differ for the same install options, e.g., if the local directory contains
+// patched manifests.
+func (s *Source) ID() string {
+	if !s.IsLocal() {
+		return "github"
+	}
+
+	var parts []string
+	if s.dir != "" {
+		parts = append(parts, "dir="+s.dir)
+	}
+	if s.embedded != nil {
+		parts = append(parts, "embedded")
+	}
+	return strings.Join(parts, ",")
+}
+
+// Versions returns the sorted list of Flux versions that are available locally.
+func (s *Source) Versions() ([]string, error) {
+	var versions []string
+	for _, fsys := range s.filesystems() {
+		entries, err := fs.ReadDir(fsys, ".")
+		if err != nil {
+			return nil, fmt.Errorf("error reading flux manifests: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !slices.Contains(versions, entry.Name()) {
+				versions = append(versions, entry.Name())
+			}
+		}
+	}
+
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// CopyTo copies the manifests of the given Flux version to the target directory, which must not contain any of the
+// manifest files yet. It returns an error wrapping ErrVersionNotAvailable if the version is not available locally.
+func (s *Source) CopyTo(version, target string) error {
+	fsys, err := s.versionFS(version)
+	if err != nil {
+		return err
+	}
+
+	if err := os.CopyFS(target, fsys); err != nil {
+		return fmt.Errorf("error copying flux manifests for version %s: %w", version, err)
+	}
+	return nil
+}
+
+func (s *Source) versionFS(version string) (fs.FS, error) {
+	if version != "" && fs.ValidPath(version) && !strings.Contains(version, "/") {
+		for _, fsys := range s.filesystems() {
+			if info, err := fs.Stat(fsys, version); err == nil && info.IsDir() {
+				return fs.Sub(fsys, version)
+			}
+		}
+	}
+
+	versions, err := s.Versions()
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: version %q is not available, available versions: [%s]", ErrVersionNotAvailable, version, strings.Join(versions, ", "))
+}
+
+func (s *Source) filesystems() []fs.FS {
+	var filesystems []fs.FS
+	if s == nil {
+		return filesystems
+	}
+	if s.dir != "" {
+		filesystems = append(filesystems, os.DirFS(s.dir))
+	}
+	if s.embedded != nil {
+		filesystems = append(filesystems, s.embedded)
+	}
+	return filesystems
+}
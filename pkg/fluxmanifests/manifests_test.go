@@ -0,0 +1,62 @@
+package fluxmanifests
+
+import (
+	"os"
+	"path/filepath"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Source", func() {
+	var (
+		dir    string
+		source *Source
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(dir, "v2.1.3"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "v2.1.3", "source-controller.yaml"), []byte("local"), 0o600)).To(Succeed())
+
+		source = NewSource(dir)
+		source.embedded = fstest.MapFS{
+			"v2.1.3/source-controller.yaml": {Data: []byte("embedded")},
+			"v2.0.0/source-controller.yaml": {Data: []byte("embedded")},
+		}
+	})
+
+	It("should not be local if nothing is configured or embedded", func() {
+		Expect((&Source{}).IsLocal()).To(BeFalse())
+		Expect((*Source)(nil).IsLocal()).To(BeFalse())
+		Expect(source.IsLocal()).To(BeTrue())
+	})
+
+	It("should list the versions of the directory and the embedded manifests", func() {
+		Expect(source.Versions()).To(Equal([]string{"v2.0.0", "v2.1.3"}))
+	})
+
+	It("should prefer the manifests in the directory", func() {
+		target := GinkgoT().TempDir()
+		Expect(source.CopyTo("v2.1.3", target)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(target, "source-controller.yaml"))).To(BeEquivalentTo("local"))
+	})
+
+	It("should fall back to the embedded manifests", func() {
+		target := GinkgoT().TempDir()
+		Expect(source.CopyTo("v2.0.0", target)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(target, "source-controller.yaml"))).To(BeEquivalentTo("embedded"))
+	})
+
+	It("should fail if the version is not available", func() {
+		err := source.CopyTo("v1.0.0", GinkgoT().TempDir())
+		Expect(err).To(MatchError(ErrVersionNotAvailable))
+		Expect(err).To(MatchError(ContainSubstring(`version "v1.0.0" is not available, available versions: [v2.0.0, v2.1.3]`)))
+	})
+
+	It("should reject versions that are not a single path element", func() {
+		Expect(source.CopyTo("../v2.1.3", GinkgoT().TempDir())).To(MatchError(ErrVersionNotAvailable))
+		Expect(source.CopyTo("", GinkgoT().TempDir())).To(MatchError(ErrVersionNotAvailable))
+	})
+})
@@ -0,0 +1,13 @@
+package fluxversions
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFluxVersions(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flux Versions Suite")
+}
@@ -0,0 +1,130 @@
+// Package fluxversions provides the catalog of Flux versions supported by the extension and resolves the configured
+// versions and semver constraints against it.
+package fluxversions
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"sigs.k8s.io/yaml"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+// ErrVersionNotSupported is returned if a configured version or constraint doesn't match any supported version.
+var ErrVersionNotSupported = errors.New("flux version is not supported")
+
+// defaultVersions is the list of versions supported by the extension, see `make update-flux-versions`.
+//
+//go:embed versions.yaml
+var defaultVersions []byte
+
+// exactVersionRegex matches exact versions as used by the Flux releases, e.g., "v2.9.2". Everything else is treated
+// as a constraint, as semver.NewVersion would also accept partial versions like "2.9".
+var exactVersionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// Catalog is a catalog of supported Flux versions with an optional minimum version.
+type Catalog struct {
+	// versions is sorted in descending order
+	versions []*semver.Version
+	minimum  *semver.Version
+}
+
+// DefaultVersions returns the versions supported by the extension, including the default Flux version.
+func DefaultVersions() ([]string, error) {
+	var versions []string
+	if err := yaml.Unmarshal(defaultVersions, &versions); err != nil {
+		return nil, fmt.Errorf("error parsing default flux versions: %w", err)
+	}
+	if !slices.Contains(versions, fluxv1alpha1.DefaultFluxVersion) {
+		versions = append(versions, fluxv1alpha1.DefaultFluxVersion)
+	}
+	return versions, nil
+}
+
+// NewCatalog returns a Catalog of the given versions. Versions below minimum are not supported. minimum may be empty.
+func NewCatalog(versions []string, minimum string) (*Catalog, error) {
+	c := &Catalog{}
+
+	for _, version := range versions {
+		if !exactVersionRegex.MatchString(version) {
+			return nil, fmt.Errorf("invalid flux version %q: must be of the form vX.Y.Z", version)
+		}
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flux version %q: %w", version, err)
+		}
+		if !slices.ContainsFunc(c.versions, v.Equal) {
+			c.versions = append(c.versions, v)
+		}
+	}
+	slices.SortFunc(c.versions, func(a, b *semver.Version) int { return b.Compare(a) })
+
+	if minimum != "" {
+		v, err := semver.NewVersion(minimum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum flux version %q: %w", minimum, err)
+		}
+		c.minimum = v
+	}
+
+	return c, nil
+}
+
+// DefaultCatalog returns a Catalog of the DefaultVersions and the given additional versions.
+func DefaultCatalog(additionalVersions []string, minimum string) (*Catalog, error) {
+	versions, err := DefaultVersions()
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalog(append(versions, additionalVersions...), minimum)
+}
+
+// Versions returns the supported versions in descending order, excluding the versions below the minimum.
+func (c *Catalog) Versions() []string {
+	var versions []string
+	for _, v := range c.versions {
+		if c.minimum == nil || !v.LessThan(c.minimum) {
+			versions = append(versions, v.Original())
+		}
+	}
+	return versions
+}
+
+// Resolve resolves the given exact version or semver constraint, e.g., "~2.9", to the newest supported version. It
+// returns an error wrapping ErrVersionNotSupported if no supported version matches.
+func (c *Catalog) Resolve(version string) (string, error) {
+	if exactVersionRegex.MatchString(version) {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("invalid flux version %q: %w", version, err)
+		}
+		if c.minimum != nil && v.LessThan(c.minimum) {
+			return "", fmt.Errorf("%w: version %q is below the minimum version %s", ErrVersionNotSupported, version, c.minimum.Original())
+		}
+		if !slices.ContainsFunc(c.versions, v.Equal) {
+			return "", c.notSupportedError(version)
+		}
+		return version, nil
+	}
+
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid flux version or constraint %q: %w", version, err)
+	}
+	for _, v := range c.versions {
+		if (c.minimum == nil || !v.LessThan(c.minimum)) && constraint.Check(v) {
+			return v.Original(), nil
+		}
+	}
+	return "", c.notSupportedError(version)
+}
+
+func (c *Catalog) notSupportedError(version string) error {
+	return fmt.Errorf("%w: version %q doesn't match any supported version, supported versions: [%s]", ErrVersionNotSupported, version, strings.Join(c.Versions(), ", "))
+}
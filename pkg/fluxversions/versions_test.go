@@ -0,0 +1,70 @@
+package fluxversions
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+)
+
+var _ = Describe("Catalog", func() {
+	var catalog *Catalog
+
+	BeforeEach(func() {
+		var err error
+		catalog, err = NewCatalog([]string{"v2.8.0", "v2.9.1", "v2.10.0-rc.1", "v2.9.2", "v2.9.1"}, "v2.9.0")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should list the supported versions in descending order", func() {
+		Expect(catalog.Versions()).To(Equal([]string{"v2.10.0-rc.1", "v2.9.2", "v2.9.1"}))
+	})
+
+	DescribeTable("should resolve versions and constraints",
+		func(version, expected string) {
+			Expect(catalog.Resolve(version)).To(Equal(expected))
+		},
+		Entry("exact version", "v2.9.1", "v2.9.1"),
+		Entry("tilde constraint", "~2.9", "v2.9.2"),
+		Entry("caret constraint", "^2", "v2.9.2"),
+		Entry("range constraint", ">= 2.9.0, < 2.9.2", "v2.9.1"),
+		Entry("version without v prefix", "2.9.1", "v2.9.1"),
+		Entry("pre-release constraint", ">= 2.10.0-rc.0", "v2.10.0-rc.1"),
+	)
+
+	It("should reject versions that are not in the catalog", func() {
+		_, err := catalog.Resolve("v2.9.3")
+		Expect(err).To(MatchError(ErrVersionNotSupported))
+		Expect(err).To(MatchError(ContainSubstring("supported versions: [v2.10.0-rc.1, v2.9.2, v2.9.1]")))
+
+		Expect(catalog.Resolve("~3.0")).Error().To(MatchError(ErrVersionNotSupported))
+	})
+
+	It("should reject versions below the minimum", func() {
+		Expect(catalog.Resolve("v2.8.0")).Error().To(MatchError(ContainSubstring(`version "v2.8.0" is below the minimum version v2.9.0`)))
+		Expect(catalog.Resolve("~2.8")).Error().To(MatchError(ErrVersionNotSupported))
+	})
+
+	It("should reject invalid constraints", func() {
+		Expect(catalog.Resolve("latest")).Error().To(MatchError(ContainSubstring(`invalid flux version or constraint "latest"`)))
+	})
+
+	It("should reject invalid catalog versions", func() {
+		Expect(NewCatalog([]string{"2.9"}, "")).Error().To(MatchError(ContainSubstring(`invalid flux version "2.9"`)))
+		Expect(NewCatalog(nil, "foo")).Error().To(MatchError(ContainSubstring(`invalid minimum flux version "foo"`)))
+	})
+
+	It("should always support the default Flux version", func() {
+		catalog, err := DefaultCatalog([]string{"v9.9.9"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catalog.Versions()).To(ContainElements(fluxv1alpha1.DefaultFluxVersion, "v9.9.9"))
+	})
+
+	It("should support the releases up to the default Flux version", func() {
+		catalog, err := DefaultCatalog(nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catalog.Resolve("v2.7.0")).To(Equal("v2.7.0"))
+		Expect(catalog.Resolve("~2.8")).To(Equal("v2.8.3"))
+		Expect(catalog.Resolve("v2.9.1")).To(Equal("v2.9.1"))
+	})
+})
@@ -0,0 +1,194 @@
+// Package offline runs the decoding, defaulting, validation, and rendering of the extension's providerConfig without
+// access to a garden, seed, or shoot cluster, e.g., to check Shoot manifests in CI.
+package offline
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardenerutils "github.com/gardener/gardener/pkg/utils/gardener"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/extension"
+)
+
+var (
+	scheme  = runtime.NewScheme()
+	decoder runtime.Decoder
+)
+
+func init() {
+	utilruntime.Must((&runtime.SchemeBuilder{
+		fluxv1alpha1.AddToScheme,
+		corev1.AddToScheme,
+		sourcev1.AddToScheme,
+		kustomizev1.AddToScheme,
+	}).AddToScheme(scheme))
+	decoder = serializer.NewCodecFactory(scheme).UniversalDecoder()
+}
+
+// Input is a providerConfig of the extension together with the Shoot it is configured for.
+type Input struct {
+	// Source describes where the input was read from, e.g., "shoot.yaml: Shoot garden-foo/bar".
+	Source string
+	// Shoot is the Shoot the providerConfig is configured for.
+	Shoot *gardencorev1beta1.Shoot
+	// ProviderConfig is the providerConfig of the extension. It may be nil.
+	ProviderConfig *runtime.RawExtension
+	// ShootManifest is true if the input was read from a Shoot manifest. Otherwise, the Shoot is constructed from the
+	// ShootMetadata and doesn't contain any resources.
+	ShootManifest bool
+	// FieldPath is the path of the providerConfig in the input document, e.g., "spec.extensions[0].providerConfig". For
+	// FluxConfig inputs, the document itself is the providerConfig and the path is "providerConfig".
+	FieldPath *field.Path
+}
+
+// ShootMetadata is used for constructing the Shoot of FluxConfig inputs, which are not embedded in a Shoot manifest.
+type ShootMetadata struct {
+	// Name is the name of the Shoot.
+	Name string
+	// Namespace is the project namespace of the Shoot, e.g., "garden-foo".
+	Namespace string
+}
+
+// ReadInputs reads the inputs from the given multi-document YAML stream. Documents can either be Shoots, which are
+// only considered if the extension is enabled for them, or FluxConfigs. The Shoot of FluxConfig documents is
+// constructed from the given metadata. name describes the stream in Input.Source.
+func ReadInputs(name string, r io.Reader, metadata ShootMetadata) ([]Input, error) {
+	var (
+		inputs []Input
+		reader = utilyaml.NewYAMLReader(bufio.NewReader(r))
+	)
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: error reading YAML: %w", name, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		docInputs, err := decodeInputs(name, doc, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		inputs = append(inputs, docInputs...)
+	}
+
+	return inputs, nil
+}
+
+func decodeInputs(name string, doc []byte, metadata ShootMetadata) ([]Input, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := yaml.Unmarshal(doc, typeMeta); err != nil {
+		return nil, fmt.Errorf("error decoding YAML: %w", err)
+	}
+
+	switch gvk := typeMeta.GroupVersionKind(); gvk {
+	case gardencorev1beta1.SchemeGroupVersion.WithKind("Shoot"):
+		shoot := &gardencorev1beta1.Shoot{}
+		if err := yaml.Unmarshal(doc, shoot); err != nil {
+			return nil, fmt.Errorf("error decoding Shoot: %w", err)
+		}
+
+		var inputs []Input
+		for i, ext := range shoot.Spec.Extensions {
+			if ext.Type != fluxv1alpha1.ExtensionType || ptr.Deref(ext.Disabled, false) {
+				continue
+			}
+			inputs = append(inputs, Input{
+				Source:         fmt.Sprintf("%s: Shoot %s/%s", name, shoot.Namespace, shoot.Name),
+				Shoot:          shoot,
+				ProviderConfig: ext.ProviderConfig,
+				ShootManifest:  true,
+				FieldPath:      field.NewPath("spec", "extensions").Index(i).Child("providerConfig"),
+			})
+		}
+		return inputs, nil
+
+	case fluxv1alpha1.SchemeGroupVersion.WithKind("FluxConfig"):
+		raw, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding FluxConfig: %w", err)
+		}
+		return []Input{{
+			Source:         fmt.Sprintf("%s: FluxConfig", name),
+			Shoot:          &gardencorev1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Name: metadata.Name, Namespace: metadata.Namespace}},
+			ProviderConfig: &runtime.RawExtension{Raw: raw},
+			FieldPath:      field.NewPath("providerConfig"),
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, expected a Shoot (%s) or a FluxConfig (%s)", gvk.Kind, gardencorev1beta1.SchemeGroupVersion, fluxv1alpha1.SchemeGroupVersion)
+	}
+}
+
+// Decode decodes and defaults the providerConfig of the given input like the extension does. It returns the
+// providerConfig together with the Shoot used for validating and rendering it. If the input was not read from a Shoot
+// manifest, the resources referenced by the providerConfig are added to Shoot.spec.resources, as they cannot be
+// checked offline.
+func Decode(input Input) (*fluxv1alpha1.FluxConfig, *gardencorev1beta1.Shoot, error) {
+	config, err := extension.DecodeProviderConfig(scheme, decoder, input.ProviderConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding providerConfig: %w", err)
+	}
+
+	shoot := input.Shoot.DeepCopy()
+	if !input.ShootManifest {
+		addReferencedResources(shoot, config)
+	}
+
+	if shoot.Status.TechnicalID == "" {
+		projectName := strings.TrimPrefix(shoot.Namespace, "garden-")
+		shoot.Status.TechnicalID = gardenerutils.ComputeTechnicalID(projectName, shoot)
+	}
+	if shoot.Status.ClusterIdentity == nil {
+		shoot.Status.ClusterIdentity = ptr.To(shoot.Status.TechnicalID)
+	}
+
+	return config, shoot, nil
+}
+
+func addReferencedResources(shoot *gardencorev1beta1.Shoot, config *fluxv1alpha1.FluxConfig) {
+	add := func(name, kind string) {
+		for _, resource := range shoot.Spec.Resources {
+			if resource.Name == name {
+				return
+			}
+		}
+		shoot.Spec.Resources = append(shoot.Spec.Resources, gardencorev1beta1.NamedResourceReference{
+			Name:        name,
+			ResourceRef: autoscalingv1.CrossVersionObjectReference{APIVersion: "v1", Kind: kind, Name: name},
+		})
+	}
+
+	if config.Source != nil && config.Source.SecretResourceName != nil {
+		add(*config.Source.SecretResourceName, "Secret")
+	}
+	for _, resource := range config.AdditionalSecretResources {
+		add(resource.Name, "Secret")
+	}
+	for _, resource := range config.AdditionalConfigMapResources {
+		add(resource.Name, "ConfigMap")
+	}
+}
@@ -0,0 +1,124 @@
+package offline
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const shootManifest = `apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: bar
+  namespace: garden-foo
+spec:
+  extensions:
+  - type: shoot-flux
+    providerConfig:
+      apiVersion: flux.extensions.gardener.cloud/v1alpha1
+      kind: FluxConfig
+      source:
+        secretResourceName: git-credentials
+        template:
+          apiVersion: source.toolkit.fluxcd.io/v1
+          kind: GitRepository
+          spec:
+            url: https://example.com/repo
+            ref:
+              branch: main
+            secretRef:
+              name: flux-system
+      kustomization:
+        template:
+          apiVersion: kustomize.toolkit.fluxcd.io/v1
+          kind: Kustomization
+          spec:
+            path: clusters/bar
+      additionalConfigMapResources:
+      - name: substitutions
+        keys:
+          cluster: ""
+  resources:
+  - name: git-credentials
+    resourceRef:
+      apiVersion: v1
+      kind: Secret
+      name: git-credentials
+  - name: substitutions
+    resourceRef:
+      apiVersion: v1
+      kind: ConfigMap
+      name: substitutions
+  provider:
+    type: local
+`
+
+var _ = Describe("ReadInputs", func() {
+	It("should read Shoots with the extension and FluxConfigs", func() {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(shootManifest+`---
+apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: without-flux
+  namespace: garden-foo
+spec:
+  extensions:
+  - type: shoot-flux
+    disabled: true
+---
+apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+`), ShootMetadata{Name: "baz", Namespace: "garden-foo"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inputs).To(HaveExactElements(
+			MatchFields(IgnoreExtras, Fields{
+				"Source":        Equal("test.yaml: Shoot garden-foo/bar"),
+				"ShootManifest": BeTrue(),
+				"FieldPath":     WithTransform((*field.Path).String, Equal("spec.extensions[0].providerConfig")),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Source":        Equal("test.yaml: FluxConfig"),
+				"Shoot":         PointTo(MatchFields(IgnoreExtras, Fields{"ObjectMeta": MatchFields(IgnoreExtras, Fields{"Name": Equal("baz")})})),
+				"ShootManifest": BeFalse(),
+				"FieldPath":     WithTransform((*field.Path).String, Equal("providerConfig")),
+			}),
+		))
+	})
+
+	It("should reject unsupported kinds", func() {
+		_, err := ReadInputs("test.yaml", strings.NewReader("apiVersion: v1\nkind: ConfigMap\n"), ShootMetadata{})
+		Expect(err).To(MatchError(ContainSubstring(`test.yaml: unsupported kind "ConfigMap"`)))
+	})
+})
+
+var _ = Describe("Decode", func() {
+	It("should default the providerConfig and the Shoot status", func() {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(shootManifest), ShootMetadata{})
+		Expect(err).NotTo(HaveOccurred())
+
+		config, shoot, err := Decode(inputs[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Flux.Namespace).To(PointTo(Equal("flux-system")))
+		Expect(shoot.Status.TechnicalID).To(Equal("shoot--foo--bar"))
+		Expect(shoot.Spec.Resources).To(HaveLen(2))
+	})
+
+	It("should add the referenced resources to the Shoot of FluxConfig inputs", func() {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(`apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+additionalSecretResources:
+- name: credentials
+`), ShootMetadata{Name: "bar", Namespace: "garden-foo"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, shoot, err := Decode(inputs[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shoot.Spec.Resources).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name":        Equal("credentials"),
+			"ResourceRef": MatchFields(IgnoreExtras, Fields{"Kind": Equal("Secret")}),
+		})))
+	})
+})
@@ -0,0 +1,243 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/gardener/gardener/pkg/extensions"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	fluxv1alpha1 "github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1/validation"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/controller/extension"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
+)
+
+// Redacted replaces data that is not available offline, i.e., the data of referenced resources, and all Secret data.
+const Redacted = "<redacted>"
+
+// RenderOptions configure Render like the corresponding options of the extension.
+type RenderOptions struct {
+	GardenClusterIdentity string
+	FluxManifests         *fluxmanifests.Source
+	ImageDigests          fluxmanifests.DigestCatalog
+	FluxVersions          *fluxversions.Catalog
+}
+
+// Render returns all objects the extension applies to the shoot when bootstrapping Flux for the given input as a
+// multi-document YAML stream: the Flux install manifest, followed by the namespaces, the source, the Kustomization,
+// the ConfigMaps, and the Secrets. To produce the same objects as the extension, the bootstrap functions of the
+// extension are run against in-memory clients. The data of referenced resources and all Secret data are redacted.
+func Render(ctx context.Context, input Input, opts RenderOptions) ([]byte, error) {
+	config, shoot, err := Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	if allErrs := validation.ValidateFluxConfig(config, shoot, opts.FluxVersions, nil); len(allErrs) > 0 {
+		return nil, fmt.Errorf("invalid providerConfig: %w", allErrs.ToAggregate())
+	}
+	if err := extension.ResolveVersion(opts.FluxVersions, config.Flux); err != nil {
+		return nil, err
+	}
+
+	installManifest, err := extension.BuildInstallManifest(config.Flux, opts.FluxManifests, nil, opts.ImageDigests)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		log           = logr.Discard()
+		cluster       = &extensions.Cluster{Shoot: shoot}
+		seedNamespace = shoot.Status.TechnicalID
+		seedClient    = fake.NewClientBuilder().WithScheme(scheme).WithObjects(referencedResourceStubs(config, shoot, seedNamespace)...).Build()
+		shootClient   = fake.NewClientBuilder().WithScheme(scheme).WithObjects(rootCAStub(*config.Flux.Namespace)).Build()
+	)
+
+	// the same sequence as in the bootstrap of the extension
+	if err := extension.ReconcileSecrets(ctx, log, nil, seedClient, shootClient, seedNamespace, config, shoot.Spec.Resources); err != nil {
+		return nil, fmt.Errorf("error rendering secrets: %w", err)
+	}
+	if err := extension.ReconcileConfigMaps(ctx, log, nil, seedClient, shootClient, seedNamespace, config, shoot.Spec.Resources); err != nil {
+		return nil, fmt.Errorf("error rendering ConfigMaps: %w", err)
+	}
+	if config.Source != nil {
+		if err := extension.BootstrapSource(ctx, log, nil, shootClient, config.Source, ptr.Deref(config.Suspend, false)); err != nil {
+			return nil, fmt.Errorf("error rendering Flux source: %w", err)
+		}
+	}
+	if err := extension.ReconcileShootInfoConfigMap(ctx, log, shootClient, config, cluster, opts.GardenClusterIdentity); err != nil {
+		return nil, fmt.Errorf("error rendering shoot info ConfigMap: %w", err)
+	}
+	if err := extension.ReconcileShootInfoSecret(ctx, log, shootClient, config, cluster); err != nil {
+		return nil, fmt.Errorf("error rendering shoot info Secret: %w", err)
+	}
+	if config.Kustomization != nil {
+		if err := extension.BootstrapKustomization(ctx, log, nil, shootClient, config.Kustomization, ptr.Deref(config.Suspend, false)); err != nil {
+			return nil, fmt.Errorf("error rendering Flux Kustomization: %w", err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("# Flux install manifest\n")
+	out.Write(installManifest)
+	if err := writeObjects(ctx, &out, shootClient, *config.Flux.Namespace); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// referencedResourceStubs returns stubs of the referenced resources, which gardener copies to the seed namespace. The
+// stubs contain the keys selected by the providerConfig with redacted values.
+func referencedResourceStubs(config *fluxv1alpha1.FluxConfig, shoot *gardencorev1beta1.Shoot, seedNamespace string) []client.Object {
+	keys := map[string][]string{}
+	for _, resource := range append(slices.Clone(config.AdditionalSecretResources), config.AdditionalConfigMapResources...) {
+		for key := range resource.Keys {
+			keys[resource.Name] = append(keys[resource.Name], key)
+		}
+	}
+
+	var stubs []client.Object
+	for _, resource := range shoot.Spec.Resources {
+		objectMeta := metav1.ObjectMeta{
+			Name:      v1beta1constants.ReferencedResourcesPrefix + resource.ResourceRef.Name,
+			Namespace: seedNamespace,
+		}
+		data := map[string]string{}
+		for _, key := range keys[resource.Name] {
+			data[key] = Redacted
+		}
+
+		switch resource.ResourceRef.Kind {
+		case "Secret":
+			stubs = append(stubs, &corev1.Secret{ObjectMeta: objectMeta, Data: stringsToBytes(data)})
+		case "ConfigMap":
+			stubs = append(stubs, &corev1.ConfigMap{ObjectMeta: objectMeta, Data: data})
+		}
+	}
+	return stubs
+}
+
+// rootCAStub returns a stub of the ConfigMap containing the shoot's CA bundle, which is published by
+// kube-controller-manager.
+func rootCAStub(namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: namespace},
+		Data:       map[string]string{"ca.crt": Redacted},
+	}
+}
+
+func stringsToBytes(data map[string]string) map[string][]byte {
+	out := make(map[string][]byte, len(data))
+	for key, value := range data {
+		out[key] = []byte(value)
+	}
+	return out
+}
+
+// writeObjects writes all objects created in the given shoot client except for the stubs and the Flux namespace,
+// which is part of the install manifest.
+func writeObjects(ctx context.Context, out *bytes.Buffer, shootClient client.Client, fluxNamespace string) error {
+	lists := []client.ObjectList{
+		&corev1.NamespaceList{},
+		&sourcev1.GitRepositoryList{},
+		&sourcev1.OCIRepositoryList{},
+		&kustomizev1.KustomizationList{},
+		&corev1.ConfigMapList{},
+		&corev1.SecretList{},
+	}
+
+	for _, list := range lists {
+		if err := shootClient.List(ctx, list); err != nil {
+			return fmt.Errorf("error listing rendered objects: %w", err)
+		}
+		objects, err := extractObjects(list)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			if obj.GetKind() == "Namespace" && obj.GetName() == fluxNamespace ||
+				obj.GetKind() == "ConfigMap" && obj.GetName() == "kube-root-ca.crt" {
+				continue
+			}
+
+			doc, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return fmt.Errorf("error encoding rendered object: %w", err)
+			}
+			out.WriteString("---\n")
+			out.Write(doc)
+		}
+	}
+	return nil
+}
+
+// extractObjects converts the items of the given list to unstructured objects without server-side fields and sorts
+// them by namespace and name. Secret data is redacted.
+func extractObjects(list client.ObjectList) ([]*unstructured.Unstructured, error) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting rendered objects: %w", err)
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, item := range items {
+		gvk, err := apiutil.GVKForObject(item, scheme)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return nil, fmt.Errorf("error converting rendered object: %w", err)
+		}
+
+		obj := &unstructured.Unstructured{Object: content}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetResourceVersion("")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(obj.Object, "status")
+
+		if gvk.Kind == "Secret" {
+			redactSecret(obj)
+		}
+		objects = append(objects, obj)
+	}
+
+	slices.SortFunc(objects, func(a, b *unstructured.Unstructured) int {
+		return strings.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName())
+	})
+	return objects, nil
+}
+
+// redactSecret replaces the data of the given Secret with stringData containing the redacted values.
+func redactSecret(obj *unstructured.Unstructured) {
+	data, _, _ := unstructured.NestedMap(obj.Object, "data")
+	unstructured.RemoveNestedField(obj.Object, "data")
+	if len(data) == 0 {
+		return
+	}
+
+	stringData := make(map[string]any, len(data))
+	for key := range data {
+		stringData[key] = Redacted
+	}
+	obj.Object["stringData"] = stringData
+}
@@ -0,0 +1,60 @@
+package offline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxmanifests"
+)
+
+var _ = Describe("Render", func() {
+	var opts RenderOptions
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.CopyFS(filepath.Join(dir, "v2.9.2"), os.DirFS("../controller/extension/testdata/fluxmanifests"))).To(Succeed())
+		opts = RenderOptions{
+			GardenClusterIdentity: "garden",
+			FluxManifests:         fluxmanifests.NewSource(dir),
+		}
+	})
+
+	It("should render all objects of the bootstrap", func() {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(shootManifest), ShootMetadata{})
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := Render(context.Background(), inputs[0], opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(And(
+			HavePrefix("# Flux install manifest\n"),
+			ContainSubstring("image: ghcr.io/fluxcd/source-controller:v1.9.3"),
+			ContainSubstring("kind: GitRepository"),
+			ContainSubstring("path: clusters/bar"),
+			ContainSubstring("SHOOT_INFO_TECHNICAL_ID: shoot--foo--bar"),
+			// the source secret
+			ContainSubstring("kind: Secret\nmetadata:\n  labels:\n    app.kubernetes.io/managed-by: gardener-extension-shoot-flux\n  name: flux-system\n  namespace: flux-system\n"),
+			// the ConfigMap with the selected key
+			ContainSubstring("data:\n  cluster: <redacted>\nkind: ConfigMap"),
+			Not(ContainSubstring("kube-root-ca.crt")),
+		))
+	})
+
+	It("should fail for invalid providerConfigs", func() {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(`apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+kustomization:
+  template:
+    spec:
+      path: clusters/bar
+`), ShootMetadata{Name: "bar", Namespace: "garden-foo"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Render(context.Background(), inputs[0], opts)
+		Expect(err).To(MatchError(ContainSubstring("invalid providerConfig")))
+	})
+})
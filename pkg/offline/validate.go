@@ -0,0 +1,20 @@
+package offline
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/apis/flux/v1alpha1/validation"
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
+)
+
+// Validate decodes, defaults, and validates the providerConfig of the given input like the extension does. For Shoot
+// manifests, this includes the cross-checks of the referenced resources against Shoot.spec.resources. If versions is
+// nil, the Flux version is not validated against the catalog of supported versions.
+func Validate(input Input, versions *fluxversions.Catalog) field.ErrorList {
+	config, shoot, err := Decode(input)
+	if err != nil {
+		return field.ErrorList{field.Invalid(input.FieldPath, "", err.Error())}
+	}
+
+	return validation.ValidateFluxConfig(config, shoot, versions, input.FieldPath)
+}
@@ -0,0 +1,64 @@
+package offline
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/stackitcloud/gardener-extension-shoot-flux/pkg/fluxversions"
+)
+
+var _ = Describe("Validate", func() {
+	readInput := func(manifest string) Input {
+		inputs, err := ReadInputs("test.yaml", strings.NewReader(manifest), ShootMetadata{Name: "bar", Namespace: "garden-foo"})
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, inputs).To(HaveLen(1))
+		return inputs[0]
+	}
+
+	It("should accept valid providerConfigs", func() {
+		Expect(Validate(readInput(shootManifest), nil)).To(BeEmpty())
+	})
+
+	It("should check the referenced resources against the Shoot resources", func() {
+		manifest, _, found := strings.Cut(shootManifest, "  resources:\n")
+		Expect(found).To(BeTrue())
+
+		Expect(Validate(readInput(manifest), nil)).To(ContainElements(
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":  Equal(field.ErrorTypeInvalid),
+				"Field": Equal("spec.extensions[0].providerConfig.source.secretResourceName"),
+			})),
+			PointTo(MatchFields(IgnoreExtras, Fields{
+				"Field": Equal("spec.extensions[0].providerConfig.additionalConfigMapResources[0].name"),
+			})),
+		))
+	})
+
+	It("should validate the Flux version against the catalog", func() {
+		versions, err := fluxversions.NewCatalog([]string{"v2.6.4"}, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(Validate(readInput(`apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+flux:
+  version: v2.5.0
+`), versions)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("providerConfig.flux.version"),
+		}))))
+	})
+
+	It("should report providerConfigs that cannot be decoded", func() {
+		Expect(Validate(readInput(`apiVersion: flux.extensions.gardener.cloud/v1alpha1
+kind: FluxConfig
+flux: foo
+`), nil)).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":  Equal(field.ErrorTypeInvalid),
+			"Field": Equal("providerConfig"),
+		}))))
+	})
+})